@@ -1,13 +1,18 @@
 package main
 
 import (
+	"backend/internal/gateway"
 	"backend/internal/handlers"
+	"backend/internal/jobs"
 	"backend/internal/middleware"
 	"backend/internal/repositories"
 	"backend/internal/services"
 	"backend/pkg/config"
 	"backend/pkg/database"
+	"backend/pkg/midtrans"
+	"context"
 	"log"
+	_ "time/tzdata" // zona waktu venue tetap tersedia di image tanpa tzdata
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,38 +29,180 @@ func main() {
 	courtRepo := repositories.NewCourtRepository(db)
 	reservationRepo := repositories.NewReservationRepository(db)
 	paymentRepo := repositories.NewPaymentRepository(db)
+	paymentNotificationRepo := repositories.NewPaymentNotificationRepository(db)
+	refundRepo := repositories.NewRefundRepository(db)
+	paymentChannelRepo := repositories.NewPaymentChannelRepository(db)
+	pricingRepo := repositories.NewPricingRepository(db)
+	closureRepo := repositories.NewClosureRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	venueRepo := repositories.NewVenueRepository(db)
+	seriesRepo := repositories.NewSeriesRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	participantRepo := repositories.NewParticipantRepository(db)
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo)
-	courtService := services.NewCourtService(courtRepo)
-	reservationService := services.NewReservationService(reservationRepo, courtRepo)
+	pricingService := services.NewPricingService(pricingRepo)
+	courtService := services.NewCourtService(courtRepo, closureRepo, pricingService)
+	auditService := services.NewAuditService(auditRepo)
+	closureService := services.NewClosureService(closureRepo, courtRepo, venueRepo, userRepo)
+	adminCourtService := services.NewAdminCourtService(courtRepo, venueRepo, userRepo)
+	adminPricingService := services.NewAdminPricingService(pricingRepo, courtRepo)
+	venueService := services.NewVenueService(venueRepo, userRepo)
 
-	// ⚠️ MidtransService TIDAK menerima client eksternal
-	midtransService := services.NewMidtransService(paymentRepo, reservationRepo)
+	// Customer dengan terlalu banyak no-show tidak bisa booking sendiri
+	noShowPolicy := services.NoShowPolicy{Limit: cfg.NoShowLimit, Window: cfg.NoShowWindow}
 
-	// ❌ Tidak ada PaymentService
-	// paymentService := services.NewPaymentService(...)  ← HAPUS
+	// Payment gateway: Midtrans, atau fake gateway untuk development offline
+	paymentGateway, fakeGateway := setupPaymentGateway(cfg)
+
+	midtransService := services.NewMidtransService(
+		paymentGateway,
+		paymentRepo,
+		participantRepo,
+		paymentNotificationRepo,
+		refundRepo,
+		cfg.TestNotificationsEnabled(),
+	)
+
+	// Waitlist: slot yang dibatalkan atau hold-nya habis ditawarkan ke antrian pertama
+	waitlistService := services.NewWaitlistService(
+		waitlistRepo,
+		reservationRepo,
+		courtRepo,
+		closureRepo,
+		pricingService,
+		noShowPolicy,
+		cfg.WaitlistClaimWindow,
+		cfg.ReservationHoldTTL,
+	)
+
+	// ReservationService butuh MidtransService untuk refund
+	reservationService := services.NewReservationService(
+		reservationRepo,
+		courtRepo,
+		closureRepo,
+		paymentRepo,
+		midtransService,
+		pricingService,
+		waitlistService,
+		noShowPolicy,
+		cfg.ReservationHoldTTL,
+	)
+
+	// PaymentService memvalidasi reservasi sebelum memanggil MidtransService
+	paymentService := services.NewPaymentService(
+		midtransService,
+		reservationRepo,
+		seriesRepo,
+		participantRepo,
+		userRepo,
+		paymentRepo,
+		paymentChannelRepo,
+	)
+
+	// Recurring bookings, paid with one payment through MidtransService
+	reservationSeriesService := services.NewReservationSeriesService(
+		seriesRepo,
+		reservationRepo,
+		courtRepo,
+		closureRepo,
+		paymentRepo,
+		midtransService,
+		pricingService,
+		noShowPolicy,
+		cfg.ReservationHoldTTL,
+	)
+
+	// Split payment: setiap pemain membayar bagiannya, organiser menutup sisanya setelah deadline
+	splitPaymentService := services.NewSplitPaymentService(
+		reservationRepo,
+		participantRepo,
+		userRepo,
+		paymentRepo,
+		midtransService,
+		cfg.SplitPaymentWindow,
+		cfg.ReservationHoldTTL,
+	)
+
+	// Front desk console: bookings of all customers, walk-ins, cash and overrides
+	adminReservationService := services.NewAdminReservationService(
+		reservationRepo,
+		courtRepo,
+		closureRepo,
+		userRepo,
+		paymentRepo,
+		refundRepo,
+		midtransService,
+		pricingService,
+		cfg.ReservationHoldTTL,
+	)
+
+	// Check-in dengan QR code di front desk; booking yang selesai ditandai completed atau no_show
+	checkInService := services.NewCheckInService(
+		reservationRepo,
+		userRepo,
+		cfg.CheckInSecret,
+		cfg.CheckInOpensBefore,
+	)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
 	courtHandler := handlers.NewCourtHandler(courtService)
 	reservationHandler := handlers.NewReservationHandler(reservationService)
+	reservationSeriesHandler := handlers.NewReservationSeriesHandler(reservationSeriesService)
+	splitPaymentHandler := handlers.NewSplitPaymentHandler(splitPaymentService)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistService)
+	closureHandler := handlers.NewClosureHandler(closureService)
+	venueHandler := handlers.NewVenueHandler(venueService, courtService)
+	adminHandler := handlers.NewAdminHandler(adminCourtService, closureService, venueService, auditService)
+	adminPricingHandler := handlers.NewAdminPricingHandler(adminPricingService)
+	adminReservationHandler := handlers.NewAdminReservationHandler(adminReservationService)
+	checkInHandler := handlers.NewCheckInHandler(checkInService)
 
-	// PaymentHandler menerima 4 parameter:
-	// (midtransService, reservationRepo, userRepo, paymentRepo)
+	// PaymentHandler menerima 3 parameter:
+	// (paymentService, midtransService, paymentRepo)
 	paymentHandler := handlers.NewPaymentHandler(
+		paymentService,
 		midtransService,
-		reservationRepo,
-		userRepo,
 		paymentRepo,
 	)
 
+	// Fake gateway mengirim notifikasi langsung ke MidtransService (in-process)
+	var fakeGatewayHandler *handlers.FakeGatewayHandler
+	if fakeGateway != nil {
+		fakeGateway.SetNotifier(func(body []byte) {
+			headers := map[string][]string{"X-Fake-Gateway": {"true"}}
+			if _, err := midtransService.HandleNotification(context.Background(), body, headers); err != nil {
+				log.Printf("❌ Fake gateway notification rejected: %v", err)
+			}
+		})
+		fakeGatewayHandler = handlers.NewFakeGatewayHandler(fakeGateway)
+	}
+
+	// Start background jobs
+	jobs.StartHoldSweeper(context.Background(), reservationService, splitPaymentService, waitlistService, cfg.HoldSweepInterval)
+	jobs.StartPaymentReconciler(context.Background(), midtransService, cfg.PaymentReconcileInterval, cfg.PaymentStaleAfter)
+	jobs.StartRefundRetrier(context.Background(), midtransService, cfg.RefundRetryInterval)
+	jobs.StartAttendanceSweeper(context.Background(), checkInService, cfg.AttendanceSweepInterval)
+
 	// Setup router
 	router := setupRouter(
+		userRepo,
 		authHandler,
 		courtHandler,
+		venueHandler,
 		reservationHandler,
+		reservationSeriesHandler,
+		splitPaymentHandler,
+		waitlistHandler,
 		paymentHandler,
+		closureHandler,
+		adminHandler,
+		adminPricingHandler,
+		adminReservationHandler,
+		checkInHandler,
+		fakeGatewayHandler,
 	)
 
 	// Start server
@@ -65,11 +212,47 @@ func main() {
 	}
 }
 
+// setupPaymentGateway returns the configured gateway, and the fake gateway itself when selected.
+// The fake gateway exposes an unauthenticated settle endpoint, so it only runs in the sandbox
+// and signs its notifications with a key of its own, never the Midtrans server key.
+func setupPaymentGateway(cfg *config.Config) (gateway.PaymentGateway, *gateway.FakeGateway) {
+	if cfg.PaymentGateway == "fake" {
+		if !cfg.FakeGatewayAllowed() {
+			log.Fatalf("PAYMENT_GATEWAY=fake is only allowed with MIDTRANS_ENV=sandbox (got %q)", cfg.MidtransEnv)
+		}
+		log.Printf("🧪 Using fake payment gateway (outcome: %s, delay: %s)", cfg.FakeGatewayOutcome, cfg.FakeGatewayDelay)
+		fakeGateway := gateway.NewFakeGateway("", cfg.FakeGatewayOutcome, cfg.FakeGatewayDelay)
+		return fakeGateway, fakeGateway
+	}
+
+	midtransConfig := midtrans.NewConfig(cfg.MidtransServerKey, cfg.MidtransClientKey, cfg.MidtransEnv)
+	midtransConfig.Timeout = cfg.MidtransTimeout
+	midtransConfig.MaxRetries = cfg.MidtransMaxRetries
+
+	midtransGateway, err := gateway.NewMidtransGateway(midtransConfig)
+	if err != nil {
+		log.Fatal("Failed to initialize Midtrans:", err)
+	}
+	log.Printf("💳 Using Midtrans payment gateway (%s)", midtransConfig.Env)
+	return midtransGateway, nil
+}
+
 func setupRouter(
+	userRepo repositories.UserRepository,
 	authHandler *handlers.AuthHandler,
 	courtHandler *handlers.CourtHandler,
+	venueHandler *handlers.VenueHandler,
 	reservationHandler *handlers.ReservationHandler,
+	reservationSeriesHandler *handlers.ReservationSeriesHandler,
+	splitPaymentHandler *handlers.SplitPaymentHandler,
+	waitlistHandler *handlers.WaitlistHandler,
 	paymentHandler *handlers.PaymentHandler,
+	closureHandler *handlers.ClosureHandler,
+	adminHandler *handlers.AdminHandler,
+	adminPricingHandler *handlers.AdminPricingHandler,
+	adminReservationHandler *handlers.AdminReservationHandler,
+	checkInHandler *handlers.CheckInHandler,
+	fakeGatewayHandler *handlers.FakeGatewayHandler,
 ) *gin.Engine {
 
 	router := gin.Default()
@@ -104,6 +287,18 @@ func setupRouter(
 		courtRoutes.GET("/:id", courtHandler.GetCourtByID)
 	}
 
+	// Public venues
+	venueRoutes := api.Group("/venues")
+	{
+		venueRoutes.GET("", venueHandler.GetVenues)
+		venueRoutes.GET("/:id", venueHandler.GetVenueByID)
+		venueRoutes.GET("/:id/courts", venueHandler.GetVenueCourts)
+		venueRoutes.GET("/:id/courts/available", venueHandler.GetVenueAvailableCourts)
+	}
+
+	// Public closures (maintenance windows, holiday blackouts)
+	api.GET("/closures", closureHandler.GetClosures)
+
 	// Protected routes
 	protected := api.Group("")
 	protected.Use(middleware.AuthMiddleware())
@@ -112,22 +307,112 @@ func setupRouter(
 
 		reservationRoutes := protected.Group("/reservations")
 		{
+			reservationRoutes.POST("/series", reservationSeriesHandler.CreateSeries)
+			reservationRoutes.GET("/series", reservationSeriesHandler.GetUserSeries)
+			reservationRoutes.GET("/series/:id", reservationSeriesHandler.GetSeries)
+			reservationRoutes.PUT("/series/:id", reservationSeriesHandler.ModifySeries)
+			reservationRoutes.PUT("/series/:id/cancel", reservationSeriesHandler.CancelSeries)
+
 			reservationRoutes.POST("", reservationHandler.CreateReservation)
 			reservationRoutes.GET("", reservationHandler.GetUserReservations)
+			reservationRoutes.GET("/no-shows", reservationHandler.GetNoShowStatus)
+			reservationRoutes.GET("/splits", splitPaymentHandler.GetUserSplits)
 			reservationRoutes.GET("/:id", reservationHandler.GetReservationByID)
 			reservationRoutes.PUT("/:id/cancel", reservationHandler.CancelReservation)
+			reservationRoutes.PUT("/:id/reschedule", reservationHandler.RescheduleReservation)
+			reservationRoutes.GET("/:id/checkin-code", checkInHandler.GetCheckInCode)
+			reservationRoutes.POST("/:id/split", splitPaymentHandler.SplitReservation)
+			reservationRoutes.GET("/:id/split", splitPaymentHandler.GetSplit)
+		}
+
+		waitlistRoutes := protected.Group("/waitlist")
+		{
+			waitlistRoutes.POST("", waitlistHandler.JoinWaitlist)
+			waitlistRoutes.GET("", waitlistHandler.GetUserEntries)
+			waitlistRoutes.DELETE("/:id", waitlistHandler.LeaveWaitlist)
+			waitlistRoutes.POST("/:id/claim", waitlistHandler.ClaimOffer)
 		}
 
 		paymentRoutes := protected.Group("/payments")
 		{
 			paymentRoutes.POST("", paymentHandler.CreatePayment)
+			paymentRoutes.POST("/series", paymentHandler.CreateSeriesPayment)
 			paymentRoutes.GET("", paymentHandler.GetUserPayments)
 			paymentRoutes.GET("/:id", paymentHandler.GetPaymentByID)
+			paymentRoutes.POST("/:id/refresh", paymentHandler.RefreshPaymentStatus)
+		}
+
+		// Admin routes
+		adminRoutes := protected.Group("/admin")
+		adminRoutes.Use(middleware.AdminMiddleware(userRepo))
+		{
+			adminRoutes.GET("/venues", adminHandler.ListVenues)
+			adminRoutes.POST("/venues", adminHandler.CreateVenue)
+			adminRoutes.PUT("/venues/:id", adminHandler.UpdateVenue)
+
+			adminRoutes.GET("/pricing-rules", adminPricingHandler.ListPricingRules)
+			adminRoutes.POST("/pricing-rules", adminPricingHandler.CreatePricingRule)
+			adminRoutes.PUT("/pricing-rules/:id", adminPricingHandler.UpdatePricingRule)
+			adminRoutes.DELETE("/pricing-rules/:id", adminPricingHandler.DeletePricingRule)
+
+			adminRoutes.GET("/holidays", adminPricingHandler.ListHolidays)
+			adminRoutes.POST("/holidays", adminPricingHandler.CreateHoliday)
+			adminRoutes.PUT("/holidays/:id", adminPricingHandler.UpdateHoliday)
+			adminRoutes.DELETE("/holidays/:id", adminPricingHandler.DeleteHoliday)
+
+			adminRoutes.PUT("/users/:id/role", adminHandler.SetUserRole)
+
+			adminRoutes.GET("/audit-logs", adminHandler.GetAuditLogs)
+		}
+
+		// Venue management (staff for their own venue, admins for all)
+		courtAdminRoutes := protected.Group("/admin/courts")
+		courtAdminRoutes.Use(middleware.RequireRole(userRepo, "staff", "admin"))
+		{
+			courtAdminRoutes.GET("", adminHandler.ListCourts)
+			courtAdminRoutes.POST("", adminHandler.CreateCourt)
+			courtAdminRoutes.GET("/:id", adminHandler.GetCourt)
+			courtAdminRoutes.PUT("/:id", adminHandler.UpdateCourt)
+			courtAdminRoutes.DELETE("/:id", adminHandler.DeactivateCourt)
+			courtAdminRoutes.PUT("/:id/hours", adminHandler.SetCourtHours)
+		}
+
+		closureAdminRoutes := protected.Group("/admin/closures")
+		closureAdminRoutes.Use(middleware.RequireRole(userRepo, "staff", "admin"))
+		{
+			closureAdminRoutes.POST("", adminHandler.CreateClosure)
+			closureAdminRoutes.DELETE("/:id", adminHandler.DeleteClosure)
+		}
+
+		// Front desk routes (staff and admins)
+		staffRoutes := protected.Group("/admin/reservations")
+		staffRoutes.Use(middleware.RequireRole(userRepo, "staff", "admin"))
+		{
+			staffRoutes.GET("", adminReservationHandler.ListReservations)
+			staffRoutes.POST("", adminReservationHandler.CreateReservation)
+			staffRoutes.GET("/:id", adminReservationHandler.GetReservation)
+			staffRoutes.POST("/:id/cash-payment", adminReservationHandler.RecordCashPayment)
+			staffRoutes.PUT("/:id/cancel", adminReservationHandler.CancelReservation)
+			staffRoutes.PUT("/:id/move", adminReservationHandler.MoveReservation)
+		}
+
+		checkInRoutes := protected.Group("/admin/checkins")
+		checkInRoutes.Use(middleware.RequireRole(userRepo, "staff", "admin"))
+		{
+			checkInRoutes.POST("", checkInHandler.CheckIn)
 		}
 	}
 
+	// Payment method catalogue (public)
+	api.GET("/payments/methods", paymentHandler.GetPaymentMethods)
+
 	// Midtrans webhook (public)
 	api.POST("/payments/notification", paymentHandler.HandlePaymentNotification)
 
+	// Fake gateway controls (only with PAYMENT_GATEWAY=fake)
+	if fakeGatewayHandler != nil {
+		api.POST("/dev/payments/:order_id/simulate", fakeGatewayHandler.Simulate)
+	}
+
 	return router
 }
@@ -0,0 +1,225 @@
+package gateway
+
+import (
+	midtransclient "backend/pkg/midtrans"
+	"backend/pkg/money"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FakeGateway is an in-process payment gateway for running the booking-to-payment flow
+// offline. It accepts every charge and, after a delay, delivers a Midtrans-format
+// notification with the configured outcome (settlement, expire or deny) to its notifier.
+type FakeGateway struct {
+	serverKey string
+	outcome   string
+	delay     time.Duration
+
+	mu           sync.Mutex
+	transactions map[string]*fakeTransaction
+	notify       func(body []byte)
+}
+
+type fakeTransaction struct {
+	Transaction
+	amount    money.Amount
+	refunded  money.Amount
+	expiresAt time.Time // zero when the order never expires
+}
+
+// NewFakeGateway signs notifications with serverKey, or with a random key generated for
+// this process when it is empty, so notifications cannot be forged from outside.
+func NewFakeGateway(serverKey, outcome string, delay time.Duration) *FakeGateway {
+	if serverKey == "" {
+		serverKey = randomServerKey()
+	}
+	return &FakeGateway{
+		serverKey:    serverKey,
+		outcome:      outcome,
+		delay:        delay,
+		transactions: make(map[string]*fakeTransaction),
+	}
+}
+
+// randomServerKey returns a fresh key for signing fake notifications.
+func randomServerKey() string {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("fake gateway: generating server key: %v", err))
+	}
+	return "fake-" + hex.EncodeToString(key)
+}
+
+// SetNotifier sets where simulated webhook bodies are delivered.
+func (g *FakeGateway) SetNotifier(notify func(body []byte)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.notify = notify
+}
+
+func (g *FakeGateway) Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	if req.Expiry != 0 && req.Expiry < MinExpiry {
+		return nil, ErrExpiryTooShort
+	}
+
+	transaction := &fakeTransaction{
+		Transaction: Transaction{
+			OrderID:           req.OrderID,
+			TransactionID:     fmt.Sprintf("fake-%d", time.Now().UnixNano()),
+			TransactionStatus: "pending",
+			GrossAmount:       req.Amount.GrossAmount(),
+			PaymentType:       req.PaymentMethod,
+		},
+		amount: req.Amount,
+	}
+	if req.Expiry > 0 {
+		transaction.expiresAt = time.Now().Add(req.Expiry)
+	}
+
+	g.mu.Lock()
+	g.transactions[req.OrderID] = transaction
+	g.mu.Unlock()
+
+	fmt.Printf("🧪 Fake gateway charge for order %s (outcome %q in %s)\n", req.OrderID, g.outcome, g.delay)
+
+	if g.outcome != "" && g.outcome != "none" {
+		orderID := req.OrderID
+		time.AfterFunc(g.delay, func() {
+			if err := g.Simulate(orderID, g.outcome); err != nil {
+				fmt.Printf("❌ Fake gateway simulation failed for %s: %v\n", orderID, err)
+			}
+		})
+	}
+
+	result := &ChargeResult{}
+	if req.PaymentMethod == "bank_transfer" {
+		result.VaBank = req.Bank
+		result.VaNumber = fmt.Sprintf("8800%012d", time.Now().UnixNano()%1_000_000_000_000)
+		if req.Bank == "mandiri" {
+			result.BillerCode = "70012"
+		}
+	} else {
+		result.SnapToken = "fake-snap-" + req.OrderID
+		result.RedirectURL = "http://localhost/fake-gateway/pay/" + req.OrderID
+	}
+	return result, nil
+}
+
+func (g *FakeGateway) GetStatus(ctx context.Context, orderID string) (*Transaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction, ok := g.transactions[orderID]
+	if !ok {
+		return nil, ErrTransactionNotFound
+	}
+	status := transaction.Transaction
+	return &status, nil
+}
+
+func (g *FakeGateway) Expire(ctx context.Context, orderID string) (*Transaction, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction, ok := g.transactions[orderID]
+	if !ok {
+		return nil, ErrTransactionNotFound
+	}
+	if transaction.TransactionStatus != "pending" && transaction.TransactionStatus != "expire" {
+		return nil, fmt.Errorf("transaction is %s and cannot be expired", transaction.TransactionStatus)
+	}
+
+	transaction.TransactionStatus = "expire"
+	status := transaction.Transaction
+	return &status, nil
+}
+
+func (g *FakeGateway) Refund(ctx context.Context, req RefundRequest) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	transaction, ok := g.transactions[req.OrderID]
+	if !ok {
+		return ErrTransactionNotFound
+	}
+	if transaction.TransactionStatus != "settlement" && transaction.TransactionStatus != "partial_refund" {
+		return fmt.Errorf("transaction is %s and cannot be refunded", transaction.TransactionStatus)
+	}
+
+	// Like Midtrans, refunds add up and may not exceed what was paid
+	if transaction.refunded+req.Amount > transaction.amount {
+		return fmt.Errorf("refund of %d exceeds the %d left to refund", req.Amount, transaction.amount-transaction.refunded)
+	}
+	transaction.refunded += req.Amount
+
+	transaction.TransactionStatus = "partial_refund"
+	if transaction.refunded >= transaction.amount {
+		transaction.TransactionStatus = "refund"
+	}
+	return nil
+}
+
+func (g *FakeGateway) VerifyNotification(body []byte) (*Transaction, error) {
+	return verifyNotification(body, g.serverKey)
+}
+
+// Simulate moves a transaction to transactionStatus (settlement, expire, deny, ...) and
+// delivers the corresponding signed notification to the notifier. Like Midtrans, an order
+// that was expired or is past its expiry can no longer be paid and expires instead of settling.
+func (g *FakeGateway) Simulate(orderID, transactionStatus string) error {
+	g.mu.Lock()
+	transaction, ok := g.transactions[orderID]
+	if !ok {
+		g.mu.Unlock()
+		return ErrTransactionNotFound
+	}
+
+	lapsed := !transaction.expiresAt.IsZero() && time.Now().After(transaction.expiresAt)
+	if transactionStatus == "settlement" && (lapsed || transaction.TransactionStatus == "expire") {
+		transactionStatus = "expire"
+	}
+	transaction.TransactionStatus = transactionStatus
+	if transactionStatus == "settlement" {
+		transaction.SettlementTime = time.Now().In(time.FixedZone("WIB", 7*60*60)).Format("2006-01-02 15:04:05")
+	}
+	notify := g.notify
+	snapshot := transaction.Transaction
+	g.mu.Unlock()
+
+	if notify == nil {
+		return nil
+	}
+
+	statusCode := "200"
+	switch transactionStatus {
+	case "pending":
+		statusCode = "201"
+	case "expire", "deny", "cancel", "failure":
+		statusCode = "202"
+	}
+
+	body, err := json.Marshal(midtransclient.Notification{
+		TransactionTime:   time.Now().Format("2006-01-02 15:04:05"),
+		TransactionStatus: snapshot.TransactionStatus,
+		TransactionID:     snapshot.TransactionID,
+		StatusCode:        statusCode,
+		SignatureKey:      midtransclient.Signature(snapshot.OrderID, statusCode, snapshot.GrossAmount, g.serverKey),
+		OrderID:           snapshot.OrderID,
+		GrossAmount:       snapshot.GrossAmount,
+		FraudStatus:       "accept",
+		Currency:          "IDR",
+		PaymentType:       snapshot.PaymentType,
+		SettlementTime:    snapshot.SettlementTime,
+	})
+	if err != nil {
+		return err
+	}
+
+	notify(body)
+	return nil
+}
@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"backend/pkg/money"
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTransactionNotFound is returned when the gateway has no transaction for the order ID.
+	ErrTransactionNotFound = errors.New("transaction not found at payment gateway")
+	// ErrInvalidSignature is returned when a notification's signature does not verify.
+	ErrInvalidSignature = errors.New("invalid notification signature")
+	// ErrInvalidNotification is returned when a notification body cannot be parsed.
+	ErrInvalidNotification = errors.New("invalid notification payload")
+	// ErrExpiryTooShort is returned when a charge would expire in less than MinExpiry.
+	ErrExpiryTooShort = errors.New("order expiry is shorter than the gateway allows")
+)
+
+// MinExpiry is the shortest order expiry a gateway accepts: Midtrans counts in whole minutes.
+const MinExpiry = time.Minute
+
+// PaymentGateway is a payment provider able to charge, report status, expire, refund and
+// sign its webhook notifications.
+type PaymentGateway interface {
+	Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error)
+	GetStatus(ctx context.Context, orderID string) (*Transaction, error)
+	// Expire closes a pending order so it can no longer be paid and returns its new state.
+	Expire(ctx context.Context, orderID string) (*Transaction, error)
+	Refund(ctx context.Context, req RefundRequest) error
+	// VerifyNotification parses a webhook body and checks its signature. On ErrInvalidSignature
+	// the parsed transaction is still returned so it can be logged.
+	VerifyNotification(body []byte) (*Transaction, error)
+}
+
+type ChargeRequest struct {
+	OrderID       string
+	Amount        money.Amount
+	PaymentMethod string
+	Bank          string // VA bank for bank_transfer
+	Customer      Customer
+	Items         []Item
+	// Expiry is how long the order stays payable; zero keeps the gateway's default.
+	// Otherwise it must be at least MinExpiry.
+	Expiry time.Duration
+}
+
+type Customer struct {
+	Name  string
+	Email string
+	Phone string
+}
+
+type Item struct {
+	ID    string
+	Name  string
+	Price money.Amount
+	Qty   int
+}
+
+// ChargeResult holds what the customer needs to complete the payment:
+// a Snap token/redirect URL or a virtual account number.
+type ChargeResult struct {
+	SnapToken   string
+	RedirectURL string
+	VaNumber    string
+	VaBank      string
+	BillerCode  string // Mandiri Bill Payment only; VaNumber holds the bill key
+}
+
+// Transaction is the gateway's view of a transaction, from a notification or a status query.
+type Transaction struct {
+	OrderID           string
+	TransactionID     string
+	TransactionStatus string
+	FraudStatus       string
+	GrossAmount       string
+	PaymentType       string
+	SettlementTime    string
+}
+
+type RefundRequest struct {
+	OrderID   string
+	RefundKey string
+	Amount    money.Amount
+	Reason    string
+}
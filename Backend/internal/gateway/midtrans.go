@@ -0,0 +1,246 @@
+package gateway
+
+import (
+	midtransclient "backend/pkg/midtrans"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MidtransGateway charges through Midtrans Snap (e-wallets, QRIS, cards) and Core API (bank transfer).
+type MidtransGateway struct {
+	serverKey string
+	client    midtransclient.Client
+}
+
+func NewMidtransGateway(config *midtransclient.Config) (*MidtransGateway, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &MidtransGateway{
+		serverKey: config.ServerKey,
+		client:    midtransclient.NewClient(config),
+	}, nil
+}
+
+func (g *MidtransGateway) Charge(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	// Gunakan SNAP untuk semua payment method kecuali bank transfer
+	if req.PaymentMethod != "bank_transfer" {
+		fmt.Printf("🔄 Using SNAP for payment method: %s\n", req.PaymentMethod)
+		return g.chargeSnap(ctx, req)
+	}
+
+	// Untuk bank transfer, gunakan Core API
+	fmt.Printf("🔄 Using CoreAPI for bank transfer\n")
+	return g.chargeCoreAPI(ctx, req)
+}
+
+// Untuk Gopay, QRIS, Credit Card, etc. (Snap Popup)
+func (g *MidtransGateway) chargeSnap(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	// Snap hanya menampilkan channel yang dipilih (sudah divalidasi terhadap katalog)
+	enabledPayments := []string{req.PaymentMethod}
+
+	fmt.Printf("✅ Enabled payments for Snap: %v\n", enabledPayments)
+
+	snapReq := &midtransclient.SnapRequest{
+		TransactionDetails: midtransclient.TransactionDetails{
+			OrderID:  req.OrderID,
+			GrossAmt: req.Amount,
+		},
+		EnabledPayments: enabledPayments,
+		CustomerDetails: midtransCustomer(req.Customer),
+		ItemDetails:     midtransItems(req.Items),
+	}
+	minutes, err := expiryMinutes(req.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	if minutes > 0 {
+		snapReq.Expiry = &midtransclient.SnapExpiry{Unit: "minute", Duration: minutes}
+	}
+
+	fmt.Printf("🔄 Sending Snap request for order: %s\n", req.OrderID)
+	snapResp, err := g.client.CreateSnapTransaction(ctx, snapReq)
+	if err != nil {
+		fmt.Printf("❌ ERROR creating Snap transaction: %v\n", err)
+		return nil, fmt.Errorf("failed to create Snap transaction: %v", err)
+	}
+
+	fmt.Printf("✅ Snap response received. Token: %s, RedirectURL: %s\n",
+		snapResp.Token, snapResp.RedirectURL)
+
+	return &ChargeResult{
+		SnapToken:   snapResp.Token,
+		RedirectURL: snapResp.RedirectURL,
+	}, nil
+}
+
+// Untuk Bank Transfer (Core API). Mandiri memakai Bill Payment (echannel), bank lain VA biasa
+func (g *MidtransGateway) chargeCoreAPI(ctx context.Context, req ChargeRequest) (*ChargeResult, error) {
+	chargeReq := &midtransclient.ChargeRequest{
+		PaymentType: "bank_transfer",
+		TransactionDetails: midtransclient.TransactionDetails{
+			OrderID:  req.OrderID,
+			GrossAmt: req.Amount,
+		},
+		CustomerDetails: midtransCustomer(req.Customer),
+		ItemDetails:     midtransItems(req.Items),
+	}
+	minutes, err := expiryMinutes(req.Expiry)
+	if err != nil {
+		return nil, err
+	}
+	if minutes > 0 {
+		chargeReq.CustomExpiry = &midtransclient.CustomExpiry{ExpiryDuration: minutes, Unit: "minute"}
+	}
+	if req.Bank == "mandiri" {
+		chargeReq.PaymentType = "echannel"
+		chargeReq.EChannel = &midtransclient.EChannel{
+			BillInfo1: "Payment for:",
+			BillInfo2: "Court booking",
+		}
+	} else {
+		chargeReq.BankTransfer = &midtransclient.BankTransfer{
+			Bank: req.Bank,
+		}
+	}
+
+	fmt.Printf("🔄 Sending CoreAPI request for bank transfer (%s), order: %s\n", req.Bank, req.OrderID)
+	coreResp, err := g.client.CreateTransaction(ctx, chargeReq)
+	if err != nil {
+		fmt.Printf("❌ ERROR creating CoreAPI transaction: %v\n", err)
+		return nil, fmt.Errorf("failed to create Core API transaction: %v", err)
+	}
+
+	fmt.Printf("✅ CoreAPI response received. Status: %s\n", coreResp.StatusMessage)
+
+	result := &ChargeResult{VaBank: req.Bank}
+	switch {
+	case coreResp.BillKey != "":
+		result.VaNumber = coreResp.BillKey
+		result.BillerCode = coreResp.BillerCode
+	case coreResp.PermataVaNumber != "":
+		result.VaNumber = coreResp.PermataVaNumber
+	case len(coreResp.VaNumbers) > 0:
+		result.VaNumber = coreResp.VaNumbers[0].VaNumber
+		result.VaBank = coreResp.VaNumbers[0].Bank
+	}
+	fmt.Printf("✅ VA Number: %s, Bank: %s\n", result.VaNumber, result.VaBank)
+
+	return result, nil
+}
+
+func (g *MidtransGateway) GetStatus(ctx context.Context, orderID string) (*Transaction, error) {
+	status, err := g.client.GetTransactionStatus(ctx, orderID)
+	if errors.Is(err, midtransclient.ErrTransactionNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		OrderID:           status.OrderID,
+		TransactionID:     status.TransactionID,
+		TransactionStatus: status.TransactionStatus,
+		FraudStatus:       status.FraudStatus,
+		GrossAmount:       status.GrossAmount,
+		PaymentType:       status.PaymentType,
+		SettlementTime:    status.SettlementTime,
+	}, nil
+}
+
+func (g *MidtransGateway) Expire(ctx context.Context, orderID string) (*Transaction, error) {
+	status, err := g.client.ExpireTransaction(ctx, orderID)
+	if errors.Is(err, midtransclient.ErrTransactionNotFound) {
+		return nil, ErrTransactionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Transaction{
+		OrderID:           status.OrderID,
+		TransactionID:     status.TransactionID,
+		TransactionStatus: status.TransactionStatus,
+		FraudStatus:       status.FraudStatus,
+		GrossAmount:       status.GrossAmount,
+		PaymentType:       status.PaymentType,
+	}, nil
+}
+
+func (g *MidtransGateway) Refund(ctx context.Context, req RefundRequest) error {
+	_, err := g.client.RefundTransaction(ctx, req.OrderID, &midtransclient.RefundRequest{
+		RefundKey: req.RefundKey,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+	})
+	if errors.Is(err, midtransclient.ErrTransactionNotFound) {
+		return ErrTransactionNotFound
+	}
+	return err
+}
+
+func (g *MidtransGateway) VerifyNotification(body []byte) (*Transaction, error) {
+	return verifyNotification(body, g.serverKey)
+}
+
+// verifyNotification parses a Midtrans-format notification and checks its signature_key.
+func verifyNotification(body []byte, serverKey string) (*Transaction, error) {
+	var notif midtransclient.Notification
+	if err := json.Unmarshal(body, &notif); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNotification, err)
+	}
+
+	transaction := &Transaction{
+		OrderID:           notif.OrderID,
+		TransactionID:     notif.TransactionID,
+		TransactionStatus: notif.TransactionStatus,
+		FraudStatus:       notif.FraudStatus,
+		GrossAmount:       notif.GrossAmount,
+		PaymentType:       notif.PaymentType,
+		SettlementTime:    notif.SettlementTime,
+	}
+
+	if !notif.VerifySignature(serverKey) {
+		return transaction, ErrInvalidSignature
+	}
+	return transaction, nil
+}
+
+// expiryMinutes converts an order expiry to whole minutes, Midtrans' smallest unit for
+// Snap. It rounds down so the order never outlives the hold; an expiry under a minute
+// cannot be expressed and is refused with ErrExpiryTooShort rather than rounded up.
+func expiryMinutes(expiry time.Duration) (int, error) {
+	if expiry == 0 {
+		return 0, nil
+	}
+	if expiry < MinExpiry {
+		return 0, ErrExpiryTooShort
+	}
+	return int(expiry / time.Minute), nil
+}
+
+func midtransCustomer(customer Customer) *midtransclient.CustomerDetails {
+	return &midtransclient.CustomerDetails{
+		FirstName: customer.Name,
+		Email:     customer.Email,
+		Phone:     customer.Phone,
+	}
+}
+
+func midtransItems(items []Item) []midtransclient.ItemDetail {
+	details := make([]midtransclient.ItemDetail, 0, len(items))
+	for _, item := range items {
+		details = append(details, midtransclient.ItemDetail{
+			ID:    item.ID,
+			Name:  item.Name,
+			Price: item.Price,
+			Qty:   item.Qty,
+		})
+	}
+	return details
+}
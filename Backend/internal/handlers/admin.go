@@ -0,0 +1,446 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	adminCourtService services.AdminCourtService
+	closureService    services.ClosureService
+	venueService      services.VenueService
+	auditService      services.AuditService
+}
+
+func NewAdminHandler(
+	adminCourtService services.AdminCourtService,
+	closureService services.ClosureService,
+	venueService services.VenueService,
+	auditService services.AuditService,
+) *AdminHandler {
+	return &AdminHandler{
+		adminCourtService: adminCourtService,
+		closureService:    closureService,
+		venueService:      venueService,
+		auditService:      auditService,
+	}
+}
+
+// ListCourts godoc
+// @Summary List all courts (staff, admin)
+// @Description List every court, including inactive and maintenance courts; staff see only their venue's courts
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/courts [get]
+func (h *AdminHandler) ListCourts(c *gin.Context) {
+	courts, err := h.adminCourtService.ListCourts(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"courts": courts,
+		"count":  len(courts),
+	})
+}
+
+// GetCourt godoc
+// @Summary Get court with operating hours (staff, admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Court ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/courts/{id} [get]
+func (h *AdminHandler) GetCourt(c *gin.Context) {
+	courtID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	court, err := h.adminCourtService.GetCourt(c.Request.Context(), c.GetUint("userID"), courtID)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"court": court})
+}
+
+// CreateCourt godoc
+// @Summary Create court (staff, admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateCourtRequest true "Court data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/courts [post]
+func (h *AdminHandler) CreateCourt(c *gin.Context) {
+	var req models.CreateCourtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	court, err := h.adminCourtService.CreateCourt(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Court created successfully",
+		"court":   court,
+	})
+}
+
+// UpdateCourt godoc
+// @Summary Update court (staff, admin)
+// @Description Update only the fields present in the body
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Court ID"
+// @Param request body models.UpdateCourtRequest true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/courts/{id} [put]
+func (h *AdminHandler) UpdateCourt(c *gin.Context) {
+	courtID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateCourtRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	court, err := h.adminCourtService.UpdateCourt(c.Request.Context(), c.GetUint("userID"), courtID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Court updated successfully",
+		"court":   court,
+	})
+}
+
+// DeactivateCourt godoc
+// @Summary Deactivate court (staff, admin)
+// @Description Set the court status to inactive; existing reservations are kept
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Court ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/courts/{id} [delete]
+func (h *AdminHandler) DeactivateCourt(c *gin.Context) {
+	courtID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	court, err := h.adminCourtService.DeactivateCourt(c.Request.Context(), c.GetUint("userID"), courtID)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Court deactivated successfully",
+		"court":   court,
+	})
+}
+
+// SetCourtHours godoc
+// @Summary Replace court weekly hours (staff, admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Court ID"
+// @Param request body models.SetOperatingHoursRequest true "Weekly hours"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/courts/{id}/hours [put]
+func (h *AdminHandler) SetCourtHours(c *gin.Context) {
+	courtID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetOperatingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	court, err := h.adminCourtService.SetOperatingHours(c.Request.Context(), c.GetUint("userID"), courtID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Operating hours updated successfully",
+		"court":   court,
+	})
+}
+
+// CreateClosure godoc
+// @Summary Close a court or the whole venue (staff, admin)
+// @Description Overlapping active reservations are flagged for rebooking or refund
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateClosureRequest true "Closure data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/closures [post]
+func (h *AdminHandler) CreateClosure(c *gin.Context) {
+	var req models.CreateClosureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	closure, flagged, err := h.closureService.CreateClosure(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":              "Closure created successfully",
+		"closure":              closure,
+		"flagged_reservations": flagged,
+	})
+}
+
+// DeleteClosure godoc
+// @Summary Remove a closure (staff, admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Closure ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /admin/closures/{id} [delete]
+func (h *AdminHandler) DeleteClosure(c *gin.Context) {
+	closureID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.closureService.DeleteClosure(c.Request.Context(), c.GetUint("userID"), closureID)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Closure removed successfully"})
+}
+
+// ListVenues godoc
+// @Summary List all venues (admin)
+// @Description List every venue, including inactive ones
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/venues [get]
+func (h *AdminHandler) ListVenues(c *gin.Context) {
+	venues, err := h.venueService.ListVenues(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venues": venues,
+		"count":  len(venues),
+	})
+}
+
+// CreateVenue godoc
+// @Summary Create venue (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateVenueRequest true "Venue data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /admin/venues [post]
+func (h *AdminHandler) CreateVenue(c *gin.Context) {
+	var req models.CreateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	venue, err := h.venueService.CreateVenue(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Venue created successfully",
+		"venue":   venue,
+	})
+}
+
+// UpdateVenue godoc
+// @Summary Update venue (admin)
+// @Description Update only the fields present in the body
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param request body models.UpdateVenueRequest true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/venues/{id} [put]
+func (h *AdminHandler) UpdateVenue(c *gin.Context) {
+	venueID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateVenueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	venue, err := h.venueService.UpdateVenue(c.Request.Context(), c.GetUint("userID"), venueID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Venue updated successfully",
+		"venue":   venue,
+	})
+}
+
+// SetUserRole godoc
+// @Summary Change a user's role (admin)
+// @Description Staff accounts must be assigned to a venue and only manage that venue
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body models.SetUserRoleRequest true "Role and venue"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	userID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetUserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	user, err := h.venueService.SetUserRole(c.Request.Context(), c.GetUint("userID"), userID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Role updated successfully",
+		"user":    user,
+	})
+}
+
+// GetAuditLogs godoc
+// @Summary List audit log entries (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param entity_type query string false "Entity type, e.g. court"
+// @Param entity_id query int false "Entity ID"
+// @Param limit query int false "Maximum entries (default 100)"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/audit-logs [get]
+func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
+	entityID, _ := strconv.Atoi(c.Query("entity_id"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	logs, err := h.auditService.GetAuditLogs(c.Request.Context(), c.Query("entity_type"), uint(entityID), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit_logs": logs,
+		"count":      len(logs),
+	})
+}
+
+func parseIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func respondAdminError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCourtNotFound),
+		errors.Is(err, services.ErrClosureNotFound),
+		errors.Is(err, services.ErrVenueNotFound),
+		errors.Is(err, services.ErrPricingRuleNotFound),
+		errors.Is(err, services.ErrHolidayNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOtherVenue):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrPricingRuleOverlap),
+		errors.Is(err, services.ErrHolidayExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
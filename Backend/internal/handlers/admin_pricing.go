@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminPricingHandler struct {
+	adminPricingService services.AdminPricingService
+}
+
+func NewAdminPricingHandler(adminPricingService services.AdminPricingService) *AdminPricingHandler {
+	return &AdminPricingHandler{adminPricingService: adminPricingService}
+}
+
+// ListPricingRules godoc
+// @Summary List a court's pricing rules (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param court_id query int true "Court ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/pricing-rules [get]
+func (h *AdminPricingHandler) ListPricingRules(c *gin.Context) {
+	courtID, err := strconv.Atoi(c.Query("court_id"))
+	if err != nil || courtID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "court_id is required"})
+		return
+	}
+
+	rules, err := h.adminPricingService.ListRules(c.Request.Context(), uint(courtID))
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pricing_rules": rules,
+		"count":         len(rules),
+	})
+}
+
+// CreatePricingRule godoc
+// @Summary Create pricing rule (admin)
+// @Description Rules of the same priority may not apply to the same time
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreatePricingRuleRequest true "Pricing rule"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/pricing-rules [post]
+func (h *AdminPricingHandler) CreatePricingRule(c *gin.Context) {
+	var req models.CreatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	rule, err := h.adminPricingService.CreateRule(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Pricing rule created successfully",
+		"pricing_rule": rule,
+	})
+}
+
+// UpdatePricingRule godoc
+// @Summary Update pricing rule (admin)
+// @Description Update only the fields present in the body
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Pricing rule ID"
+// @Param request body models.UpdatePricingRuleRequest true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/pricing-rules/{id} [put]
+func (h *AdminPricingHandler) UpdatePricingRule(c *gin.Context) {
+	ruleID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdatePricingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	rule, err := h.adminPricingService.UpdateRule(c.Request.Context(), c.GetUint("userID"), ruleID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Pricing rule updated successfully",
+		"pricing_rule": rule,
+	})
+}
+
+// DeletePricingRule godoc
+// @Summary Delete pricing rule (admin)
+// @Description Existing bookings keep the price they were quoted
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Pricing rule ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/pricing-rules/{id} [delete]
+func (h *AdminPricingHandler) DeletePricingRule(c *gin.Context) {
+	ruleID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminPricingService.DeleteRule(c.Request.Context(), c.GetUint("userID"), ruleID); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pricing rule deleted successfully"})
+}
+
+// ListHolidays godoc
+// @Summary List holidays (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/holidays [get]
+func (h *AdminPricingHandler) ListHolidays(c *gin.Context) {
+	holidays, err := h.adminPricingService.ListHolidays(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"holidays": holidays,
+		"count":    len(holidays),
+	})
+}
+
+// CreateHoliday godoc
+// @Summary Create holiday (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateHolidayRequest true "Holiday"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/holidays [post]
+func (h *AdminPricingHandler) CreateHoliday(c *gin.Context) {
+	var req models.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	holiday, err := h.adminPricingService.CreateHoliday(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Holiday created successfully",
+		"holiday": holiday,
+	})
+}
+
+// UpdateHoliday godoc
+// @Summary Update holiday (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Param request body models.UpdateHolidayRequest true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/holidays/{id} [put]
+func (h *AdminPricingHandler) UpdateHoliday(c *gin.Context) {
+	holidayID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	holiday, err := h.adminPricingService.UpdateHoliday(c.Request.Context(), c.GetUint("userID"), holidayID, &req)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Holiday updated successfully",
+		"holiday": holiday,
+	})
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday (admin)
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/holidays/{id} [delete]
+func (h *AdminPricingHandler) DeleteHoliday(c *gin.Context) {
+	holidayID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.adminPricingService.DeleteHoliday(c.Request.Context(), c.GetUint("userID"), holidayID); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted successfully"})
+}
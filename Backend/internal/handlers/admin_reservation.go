@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminReservationHandler struct {
+	adminReservationService services.AdminReservationService
+}
+
+func NewAdminReservationHandler(adminReservationService services.AdminReservationService) *AdminReservationHandler {
+	return &AdminReservationHandler{adminReservationService: adminReservationService}
+}
+
+// ListReservations godoc
+// @Summary List reservations of all customers (staff)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First date in YYYY-MM-DD format"
+// @Param to query string false "Last date in YYYY-MM-DD format (inclusive)"
+// @Param venue_id query int false "Venue ID (staff always see their own venue)"
+// @Param court_id query int false "Court ID"
+// @Param status query string false "Reservation status"
+// @Param q query string false "Customer name, email or guest name"
+// @Param limit query int false "Page size (default 50)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /admin/reservations [get]
+func (h *AdminReservationHandler) ListReservations(c *gin.Context) {
+	filter := repositories.ReservationFilter{
+		Status:   c.Query("status"),
+		Customer: c.Query("q"),
+	}
+
+	var ok bool
+	if filter.From, ok = optionalDateQuery(c, "from"); !ok {
+		return
+	}
+	if filter.To, ok = optionalDateQuery(c, "to"); !ok {
+		return
+	}
+
+	venueID, _ := strconv.Atoi(c.Query("venue_id"))
+	filter.VenueID = uint(venueID)
+	courtID, _ := strconv.Atoi(c.Query("court_id"))
+	filter.CourtID = uint(courtID)
+	filter.Limit, _ = strconv.Atoi(c.Query("limit"))
+	filter.Offset, _ = strconv.Atoi(c.Query("offset"))
+
+	reservations, total, err := h.adminReservationService.ListReservations(c.Request.Context(), c.GetUint("userID"), filter)
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservations": reservations,
+		"count":        len(reservations),
+		"total":        total,
+	})
+}
+
+// GetReservation godoc
+// @Summary Get any reservation (staff)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/reservations/{id} [get]
+func (h *AdminReservationHandler) GetReservation(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.adminReservationService.GetReservation(c.Request.Context(), c.GetUint("userID"), reservationID)
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reservation": reservation})
+}
+
+// CreateReservation godoc
+// @Summary Book for a customer or walk-in guest (staff)
+// @Description Provide user_id for a registered customer or guest_name for a walk-in; cash_paid confirms the booking at once
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AdminCreateReservationRequest true "Reservation data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/reservations [post]
+func (h *AdminReservationHandler) CreateReservation(c *gin.Context) {
+	var req models.AdminCreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	reservation, err := h.adminReservationService.CreateReservation(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":     "Reservation created successfully",
+		"reservation": reservation,
+	})
+}
+
+// RecordCashPayment godoc
+// @Summary Record a cash payment (staff)
+// @Description Mark a pending reservation as paid in cash at the front desk and confirm it
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /admin/reservations/{id}/cash-payment [post]
+func (h *AdminReservationHandler) RecordCashPayment(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	payment, err := h.adminReservationService.RecordCashPayment(c.Request.Context(), c.GetUint("userID"), reservationID)
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Cash payment recorded",
+		"payment": payment,
+	})
+}
+
+// CancelReservation godoc
+// @Summary Force-cancel a reservation (staff)
+// @Description Cancel regardless of the cancellation policy, optionally refunding the booking amount in full
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Param request body models.ForceCancelReservationRequest true "Reason and refund choice"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /admin/reservations/{id}/cancel [put]
+func (h *AdminReservationHandler) CancelReservation(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ForceCancelReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	refunds, err := h.adminReservationService.CancelReservation(c.Request.Context(), c.GetUint("userID"), reservationID, &req)
+	if errors.Is(err, services.ErrRefundFailed) {
+		// The booking is cancelled; report the refunds that did go through
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "refunds": refunds})
+		return
+	}
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	response := gin.H{"message": "Reservation cancelled successfully"}
+	if len(refunds) > 0 {
+		response["refund"] = refunds[0]
+		response["refunds"] = refunds
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// MoveReservation godoc
+// @Summary Move a reservation (staff)
+// @Description Move a booking to another start time, date or court; duration and price stay the same
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Param request body models.MoveReservationRequest true "New court, date and start time"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/reservations/{id}/move [put]
+func (h *AdminReservationHandler) MoveReservation(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.MoveReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	reservation, err := h.adminReservationService.MoveReservation(c.Request.Context(), c.GetUint("userID"), reservationID, &req)
+	if err != nil {
+		respondReservationOverrideError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Reservation moved successfully",
+		"reservation": reservation,
+	})
+}
+
+// optionalDateQuery parses a YYYY-MM-DD query parameter as a calendar date, like the
+// reservation dates it is compared with; it responds with 400 when invalid.
+func optionalDateQuery(c *gin.Context, param string) (*time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return nil, true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + " date. Use YYYY-MM-DD"})
+		return nil, false
+	}
+	return &parsed, true
+}
+
+func respondReservationOverrideError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound),
+		errors.Is(err, services.ErrCourtNotFound),
+		errors.Is(err, services.ErrVenueNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrOtherVenue):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSlotTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrRefundFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
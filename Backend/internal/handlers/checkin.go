@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckInHandler struct {
+	checkInService services.CheckInService
+}
+
+func NewCheckInHandler(checkInService services.CheckInService) *CheckInHandler {
+	return &CheckInHandler{checkInService: checkInService}
+}
+
+// GetCheckInCode godoc
+// @Summary Get the check-in code of a reservation
+// @Description Get the signed code of a confirmed reservation, to show as a QR code at the front desk. Rescheduling the reservation issues a new code
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.CheckInCodeResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /reservations/{id}/checkin-code [get]
+func (h *CheckInHandler) GetCheckInCode(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.checkInService.GetCheckInCode(c.Request.Context(), c.GetUint("userID"), reservationID)
+	if err != nil {
+		respondCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
+
+// CheckIn godoc
+// @Summary Check in a reservation (staff)
+// @Description Validate a scanned check-in code and mark the booking as checked in. Check-in opens shortly before the start and closes when the slot ends
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CheckInRequest true "Scanned code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /admin/checkins [post]
+func (h *CheckInHandler) CheckIn(c *gin.Context) {
+	var req models.CheckInRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	reservation, err := h.checkInService.CheckIn(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondCheckInError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Checked in successfully",
+		"reservation": reservation,
+	})
+}
+
+func respondCheckInError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotReservationOwner),
+		errors.Is(err, services.ErrOtherVenue):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadyCheckedIn),
+		errors.Is(err, services.ErrCheckInClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClosureHandler struct {
+	closureService services.ClosureService
+}
+
+func NewClosureHandler(closureService services.ClosureService) *ClosureHandler {
+	return &ClosureHandler{closureService: closureService}
+}
+
+// GetClosures godoc
+// @Summary List closures
+// @Description List court and venue closures overlapping a date range (default: the next 30 days). Dates are taken in the venue's timezone.
+// @Tags closures
+// @Produce json
+// @Param venue_id query int false "Venue whose timezone the dates are in (default: the default venue timezone)"
+// @Param from query string false "Start date in YYYY-MM-DD format"
+// @Param to query string false "End date in YYYY-MM-DD format (inclusive)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /closures [get]
+func (h *ClosureHandler) GetClosures(c *gin.Context) {
+	var venueID *uint
+	if value := c.Query("venue_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid venue_id"})
+			return
+		}
+		parsed := uint(id)
+		venueID = &parsed
+	}
+	from, ok := optionalDateQuery(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := optionalDateQuery(c, "to")
+	if !ok {
+		return
+	}
+
+	closures, err := h.closureService.GetClosures(c.Request.Context(), venueID, from, to)
+	if errors.Is(err, services.ErrVenueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"closures": closures,
+		"count":    len(closures),
+	})
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"backend/internal/models"
 	"backend/internal/services"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -112,7 +113,7 @@ func (h *CourtHandler) CheckAvailability(c *gin.Context) {
 
 // GetCourtByID godoc
 // @Summary Get court by ID
-// @Description Get specific court details
+// @Description Get full court details: amenities, photos, pricing rules, weekly hours and today's slots
 // @Tags courts
 // @Accept json
 // @Produce json
@@ -120,6 +121,7 @@ func (h *CourtHandler) CheckAvailability(c *gin.Context) {
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
 // @Router /courts/{id} [get]
 func (h *CourtHandler) GetCourtByID(c *gin.Context) {
 	var req struct {
@@ -134,12 +136,18 @@ func (h *CourtHandler) GetCourtByID(c *gin.Context) {
 	}
 
 	court, err := h.courtService.GetCourtByID(c.Request.Context(), req.ID)
-	if err != nil {
+	if errors.Is(err, services.ErrCourtNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": "Court not found",
 		})
 		return
 	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to get court",
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"court": court,
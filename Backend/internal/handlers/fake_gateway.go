@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"backend/internal/gateway"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FakeGatewayHandler lets developers trigger payment outcomes when PAYMENT_GATEWAY=fake.
+type FakeGatewayHandler struct {
+	gateway *gateway.FakeGateway
+}
+
+func NewFakeGatewayHandler(fakeGateway *gateway.FakeGateway) *FakeGatewayHandler {
+	return &FakeGatewayHandler{gateway: fakeGateway}
+}
+
+func (h *FakeGatewayHandler) Simulate(c *gin.Context) {
+	var req struct {
+		TransactionStatus string `json:"transaction_status" binding:"required,oneof=settlement pending expire deny cancel failure"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.gateway.Simulate(c.Param("order_id"), req.TransactionStatus)
+	if errors.Is(err, gateway.ErrTransactionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification simulated", "transaction_status": req.TransactionStatus})
+}
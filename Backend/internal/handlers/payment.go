@@ -4,32 +4,28 @@ import (
 	"backend/internal/models"
 	"backend/internal/repositories"
 	"backend/internal/services"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type PaymentHandler struct {
-	paymentService  services.MidtransService
-	reservationRepo repositories.ReservationRepository
-	userRepo        repositories.UserRepository
+	paymentService  services.PaymentService
+	midtransService services.MidtransService
 	paymentRepo     repositories.PaymentRepository
 }
 
 func NewPaymentHandler(
-	paymentService services.MidtransService,
-	reservationRepo repositories.ReservationRepository,
-	userRepo repositories.UserRepository,
+	paymentService services.PaymentService,
+	midtransService services.MidtransService,
 	paymentRepo repositories.PaymentRepository,
 ) *PaymentHandler {
 	return &PaymentHandler{
 		paymentService:  paymentService,
-		reservationRepo: reservationRepo,
-		userRepo:        userRepo,
+		midtransService: midtransService,
 		paymentRepo:     paymentRepo,
 	}
 }
@@ -47,34 +43,62 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 		return
 	}
 
-	// Get Reservation
-	reservation, err := h.reservationRepo.GetReservationByID(c, req.ReservationID)
+	// Create Payment (ownership, state and duplicate checks in PaymentService)
+	paymentResp, err := h.paymentService.CreatePayment(c.Request.Context(), userID.(uint), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Reservation not found"})
+		respondCreatePaymentError(c, err)
 		return
 	}
 
-	// Get User
-	user, err := h.userRepo.GetUserByID(c, userID.(uint))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+	c.JSON(http.StatusCreated, paymentCreatedResponse(paymentResp, req.PaymentMethod))
+}
+
+// CreateSeriesPayment starts one payment for every occurrence of a reservation series.
+func (h *PaymentHandler) CreateSeriesPayment(c *gin.Context) {
+	var req models.CreateSeriesPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Create Payment - SEKARANG menggunakan PaymentResponse
-	paymentResp, err := h.paymentService.CreatePayment(c, reservation, user, req.PaymentMethod)
+	paymentResp, err := h.paymentService.CreateSeriesPayment(c.Request.Context(), c.GetUint("userID"), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondCreatePaymentError(c, err)
 		return
 	}
 
+	c.JSON(http.StatusCreated, paymentCreatedResponse(paymentResp, req.PaymentMethod))
+}
+
+func respondCreatePaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrUnsupportedPaymentMethod):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReservationNotFound),
+		errors.Is(err, services.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotReservationOwner),
+		errors.Is(err, services.ErrNotSeriesOwner),
+		errors.Is(err, services.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrReservationNotPayable),
+		errors.Is(err, services.ErrAlreadyPaid),
+		errors.Is(err, services.ErrPaymentInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func paymentCreatedResponse(paymentResp *services.PaymentResponse, paymentMethod string) gin.H {
 	// Return response berdasarkan payment method
 	response := gin.H{
 		"message":        "Payment created successfully",
 		"order_id":       paymentResp.OrderID,
 		"amount":         paymentResp.Amount,
+		"fee":            paymentResp.Fee,
 		"status":         paymentResp.Status,
-		"payment_method": req.PaymentMethod,
+		"payment_method": paymentMethod,
 	}
 
 	// Tambahkan fields berdasarkan jenis payment
@@ -87,19 +111,29 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 	if paymentResp.VaNumber != "" {
 		response["va_number"] = paymentResp.VaNumber
 		response["va_bank"] = paymentResp.VaBank
+		if paymentResp.BillerCode != "" {
+			response["biller_code"] = paymentResp.BillerCode
+		}
 		fmt.Printf("Bank Transfer - VA: %s, Bank: %s\n", paymentResp.VaNumber, paymentResp.VaBank)
 	}
 
-	c.JSON(http.StatusCreated, response)
+	return response
 }
 
-// Handler lainnya tetap sama...
-func (h *PaymentHandler) HandlePaymentNotification(c *gin.Context) {
-	// Log everything about the request
-	fmt.Printf("=== RAW REQUEST INSPECTION ===\n")
-	fmt.Printf("Content-Type: %s\n", c.GetHeader("Content-Type"))
-	fmt.Printf("Content-Length: %s\n", c.GetHeader("Content-Length"))
+func (h *PaymentHandler) GetPaymentMethods(c *gin.Context) {
+	methods, err := h.paymentService.GetPaymentMethods(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payment_methods": methods,
+		"count":           len(methods),
+	})
+}
 
+func (h *PaymentHandler) HandlePaymentNotification(c *gin.Context) {
 	// Read raw body
 	body, err := c.GetRawData()
 	if err != nil {
@@ -108,47 +142,26 @@ func (h *PaymentHandler) HandlePaymentNotification(c *gin.Context) {
 		return
 	}
 
-	fmt.Printf("Raw body as string: %s\n", string(body))
-	fmt.Printf("Raw body length: %d\n", len(body))
-
-	// ✅ FIX: Handle empty body (Midtrans test notification)
-	if len(body) == 0 {
-		fmt.Printf("INFO: Empty request body received - Midtrans test notification\n")
-		// Return 200 OK untuk test notification
-		c.JSON(http.StatusOK, gin.H{"message": "Test notification received successfully"})
-		return
-	}
-
-	// Try to parse as JSON
-	var payload map[string]interface{}
-	if err := json.Unmarshal(body, &payload); err != nil {
-		fmt.Printf("ERROR unmarshaling JSON: %v\n", err)
-		fmt.Printf("Error details: %s\n", err.Error())
-
-		// Try to see what's wrong with the JSON
-		if syntaxErr, ok := err.(*json.SyntaxError); ok {
-			fmt.Printf("Syntax error at offset %d\n", syntaxErr.Offset)
+	// Process (and log) the notification
+	notification, err := h.midtransService.HandleNotification(c.Request.Context(), body, c.Request.Header)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidSignature):
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrEmptyNotification),
+			errors.Is(err, services.ErrInvalidNotification),
+			errors.Is(err, services.ErrAmountMismatch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		}
-
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON payload: " + err.Error()})
 		return
 	}
 
-	fmt.Printf("Successfully parsed payload: %+v\n", payload)
-
-	// ✅ FIX: Handle test notifications from Midtrans
-	if orderID, exists := payload["order_id"]; exists {
-		orderIDStr := fmt.Sprintf("%v", orderID)
-		if strings.Contains(orderIDStr, "payment_notif_test") || strings.Contains(orderIDStr, "test") {
-			fmt.Printf("INFO: Midtrans test notification - OrderID: %s\n", orderIDStr)
-			c.JSON(http.StatusOK, gin.H{"message": "Test notification processed successfully"})
-			return
-		}
-	}
-
-	// Process the notification
-	if err := h.paymentService.HandleNotification(c.Request.Context(), payload); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if notification.Outcome == "test" {
+		c.JSON(http.StatusOK, gin.H{"message": "Test notification received successfully"})
 		return
 	}
 
@@ -192,3 +205,30 @@ func (h *PaymentHandler) GetPaymentByID(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"payment": payment})
 }
+
+func (h *PaymentHandler) RefreshPaymentStatus(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	paymentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	payment, err := h.midtransService.RefreshPaymentStatus(c.Request.Context(), uint(paymentID), userID.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		default:
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"backend/internal/gateway"
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/internal/services"
+	midtransclient "backend/pkg/midtrans"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const testServerKey = "SB-Mid-server-test"
+
+// stubPaymentRepo serves payments from memory; methods the webhook does not use panic.
+type stubPaymentRepo struct {
+	repositories.PaymentRepository
+	payments map[string]*models.Payment
+	settled  []string
+}
+
+func (r *stubPaymentRepo) GetPaymentByOrderID(ctx context.Context, orderID string) (*models.Payment, error) {
+	payment, ok := r.payments[orderID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *payment
+	return &copied, nil
+}
+
+func (r *stubPaymentRepo) SettleReservationPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, bool, error) {
+	r.settled = append(r.settled, payment.MidtransOrderID)
+	return true, true, nil
+}
+
+type stubNotificationRepo struct {
+	notifications []models.PaymentNotification
+}
+
+func (r *stubNotificationRepo) CreateNotification(ctx context.Context, notification *models.PaymentNotification) error {
+	r.notifications = append(r.notifications, *notification)
+	return nil
+}
+
+// notificationBody builds a settlement notification whose signature covers signedAmount
+// while the body reports grossAmount.
+func notificationBody(t *testing.T, orderID, grossAmount, signedAmount string) []byte {
+	t.Helper()
+	body, err := json.Marshal(midtransclient.Notification{
+		TransactionStatus: "settlement",
+		TransactionID:     "txn-" + orderID,
+		StatusCode:        "200",
+		SignatureKey:      midtransclient.Signature(orderID, "200", signedAmount, testServerKey),
+		OrderID:           orderID,
+		GrossAmount:       grossAmount,
+		FraudStatus:       "accept",
+		PaymentType:       "bank_transfer",
+	})
+	if err != nil {
+		t.Fatalf("marshalling notification: %v", err)
+	}
+	return body
+}
+
+func TestHandlePaymentNotification(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	const orderID = "ORDER-42-1700000000"
+
+	tamperedSignature := func(t *testing.T) []byte {
+		var notif midtransclient.Notification
+		json.Unmarshal(notificationBody(t, orderID, "150000.00", "150000.00"), &notif)
+		notif.SignatureKey = midtransclient.Signature(orderID, "200", "150000.00", "SB-Mid-server-forged")
+		body, _ := json.Marshal(notif)
+		return body
+	}
+
+	tests := []struct {
+		name        string
+		body        func(t *testing.T) []byte
+		wantStatus  int
+		wantSettled bool
+		wantOutcome string
+	}{
+		{
+			name:        "valid signature",
+			body:        func(t *testing.T) []byte { return notificationBody(t, orderID, "150000.00", "150000.00") },
+			wantStatus:  http.StatusOK,
+			wantSettled: true,
+			wantOutcome: "processed",
+		},
+		{
+			name:        "tampered signature_key",
+			body:        tamperedSignature,
+			wantStatus:  http.StatusUnauthorized,
+			wantOutcome: "rejected",
+		},
+		{
+			name:        "tampered gross_amount",
+			body:        func(t *testing.T) []byte { return notificationBody(t, orderID, "1000.00", "150000.00") },
+			wantStatus:  http.StatusUnauthorized,
+			wantOutcome: "rejected",
+		},
+		{
+			name:        "signed gross_amount differing from the payment",
+			body:        func(t *testing.T) []byte { return notificationBody(t, orderID, "1000.00", "1000.00") },
+			wantStatus:  http.StatusBadRequest,
+			wantOutcome: "rejected",
+		},
+		{
+			name:        "unknown order",
+			body:        func(t *testing.T) []byte { return notificationBody(t, "ORDER-99-1700000000", "150000.00", "150000.00") },
+			wantStatus:  http.StatusNotFound,
+			wantOutcome: "rejected",
+		},
+		{
+			name:        "empty body",
+			body:        func(t *testing.T) []byte { return nil },
+			wantStatus:  http.StatusBadRequest,
+			wantOutcome: "rejected",
+		},
+		{
+			name:        "malformed body",
+			body:        func(t *testing.T) []byte { return []byte(`{"order_id":`) },
+			wantStatus:  http.StatusBadRequest,
+			wantOutcome: "rejected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paymentRepo := &stubPaymentRepo{payments: map[string]*models.Payment{
+				orderID: {ID: 1, ReservationID: 42, MidtransOrderID: orderID, Amount: 150000, Status: "pending", Purpose: "booking"},
+			}}
+			notificationRepo := &stubNotificationRepo{}
+			midtransService := services.NewMidtransService(
+				gateway.NewFakeGateway(testServerKey, "none", 0),
+				paymentRepo,
+				nil,
+				notificationRepo,
+				nil,
+				false,
+			)
+			handler := NewPaymentHandler(nil, midtransService, paymentRepo)
+
+			router := gin.New()
+			router.POST("/payments/notification", handler.HandlePaymentNotification)
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/payments/notification", bytes.NewReader(tt.body(t)))
+			router.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", recorder.Code, tt.wantStatus, recorder.Body.String())
+			}
+			if settled := len(paymentRepo.settled) > 0; settled != tt.wantSettled {
+				t.Errorf("payment settled = %v, want %v", settled, tt.wantSettled)
+			}
+			if len(notificationRepo.notifications) != 1 {
+				t.Fatalf("%d notifications logged, want 1", len(notificationRepo.notifications))
+			}
+			if outcome := notificationRepo.notifications[0].Outcome; outcome != tt.wantOutcome {
+				t.Errorf("logged outcome = %q, want %q", outcome, tt.wantOutcome)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"backend/internal/models"
 	"backend/internal/services"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -28,6 +29,8 @@ func NewReservationHandler(reservationService services.ReservationService) *Rese
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /reservations [post]
 func (h *ReservationHandler) CreateReservation(c *gin.Context) {
@@ -53,6 +56,19 @@ func (h *ReservationHandler) CreateReservation(c *gin.Context) {
 		userID.(uint),
 		&req,
 	)
+	if errors.Is(err, services.ErrSlotTaken) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    err.Error(),
+			"waitlist": "Join the waitlist with POST /api/v1/waitlist to be offered the slot if it frees up",
+		})
+		return
+	}
+	if errors.Is(err, services.ErrBookingRestricted) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
@@ -100,6 +116,27 @@ func (h *ReservationHandler) GetUserReservations(c *gin.Context) {
 	})
 }
 
+// GetNoShowStatus godoc
+// @Summary Get no-show status
+// @Description Get how many bookings the authenticated user missed recently and whether that blocks new bookings
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.NoShowStatus
+// @Failure 500 {object} map[string]interface{}
+// @Router /reservations/no-shows [get]
+func (h *ReservationHandler) GetNoShowStatus(c *gin.Context) {
+	status, err := h.reservationService.GetNoShowStatus(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
 // GetReservationByID godoc
 // @Summary Get reservation by ID
 // @Description Get specific reservation details
@@ -150,7 +187,7 @@ func (h *ReservationHandler) GetReservationByID(c *gin.Context) {
 
 // CancelReservation godoc
 // @Summary Cancel reservation
-// @Description Cancel a pending reservation
+// @Description Cancel a pending reservation, or a confirmed one with a refund per the court's cancellation policy
 // @Tags reservations
 // @Accept json
 // @Produce json
@@ -179,7 +216,7 @@ func (h *ReservationHandler) CancelReservation(c *gin.Context) {
 		return
 	}
 
-	err = h.reservationService.CancelReservation(
+	refunds, err := h.reservationService.CancelReservation(
 		c.Request.Context(),
 		uint(reservationID),
 		userID.(uint),
@@ -191,7 +228,89 @@ func (h *ReservationHandler) CancelReservation(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Reservation cancelled successfully",
-	})
+	}
+	if len(refunds) > 0 {
+		response["refund"] = refunds[0]
+		response["refunds"] = refunds
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RescheduleReservation godoc
+// @Summary Reschedule reservation
+// @Description Move a pending or confirmed reservation to another slot or court until the court's reschedule cutoff. The booking is re-priced; a cheaper slot is refunded the difference, a pricier one leaves amount_due to pay with POST /payments
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Param request body models.RescheduleReservationRequest true "New slot"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 502 {object} map[string]interface{}
+// @Router /reservations/{id}/reschedule [put]
+func (h *ReservationHandler) RescheduleReservation(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.RescheduleReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid input",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	reservation, refunds, err := h.reservationService.RescheduleReservation(
+		c.Request.Context(),
+		reservationID,
+		c.GetUint("userID"),
+		&req,
+	)
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrNotReservationOwner):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrSlotTaken):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    err.Error(),
+			"waitlist": "Join the waitlist with POST /api/v1/waitlist to be offered the slot if it frees up",
+		})
+		return
+	case errors.Is(err, services.ErrRescheduleCutoff),
+		errors.Is(err, services.ErrPaymentInProgress),
+		errors.Is(err, services.ErrReservationChanged):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrRefundFailed):
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "refunds": refunds})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := gin.H{
+		"message":     "Reservation rescheduled successfully",
+		"reservation": reservation,
+		"amount_due":  reservation.AmountDue,
+	}
+	if len(refunds) > 0 {
+		response["refund"] = refunds[0]
+		response["refunds"] = refunds
+	}
+
+	c.JSON(http.StatusOK, response)
 }
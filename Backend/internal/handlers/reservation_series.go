@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReservationSeriesHandler struct {
+	seriesService services.ReservationSeriesService
+}
+
+func NewReservationSeriesHandler(seriesService services.ReservationSeriesService) *ReservationSeriesHandler {
+	return &ReservationSeriesHandler{seriesService: seriesService}
+}
+
+// CreateSeries godoc
+// @Summary Create a recurring reservation series
+// @Description Book the same time on the same courts every week or every other week, until end_date or for a number of occurrences. Nothing is booked if any occurrence conflicts; the conflicts are listed in the response
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateSeriesRequest true "Series data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /reservations/series [post]
+func (h *ReservationSeriesHandler) CreateSeries(c *gin.Context) {
+	var req models.CreateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Reservation series created successfully",
+		"series":  series,
+	})
+}
+
+// GetUserSeries godoc
+// @Summary Get user reservation series
+// @Description Get all recurring reservation series of the authenticated user with their occurrences
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /reservations/series [get]
+func (h *ReservationSeriesHandler) GetUserSeries(c *gin.Context) {
+	series, err := h.seriesService.GetUserSeries(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"series": series,
+		"count":  len(series),
+	})
+}
+
+// GetSeries godoc
+// @Summary Get reservation series by ID
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /reservations/series/{id} [get]
+func (h *ReservationSeriesHandler) GetSeries(c *gin.Context) {
+	seriesID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	series, err := h.seriesService.GetSeries(c.Request.Context(), c.GetUint("userID"), seriesID)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"series": series})
+}
+
+// ModifySeries godoc
+// @Summary Move a reservation series to a new start time
+// @Description Move every upcoming occurrence to the new start time on the same day and court, keeping the duration and price
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Param request body models.ModifySeriesRequest true "New start time"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /reservations/series/{id} [put]
+func (h *ReservationSeriesHandler) ModifySeries(c *gin.Context) {
+	seriesID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.ModifySeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	series, err := h.seriesService.ModifySeries(c.Request.Context(), c.GetUint("userID"), seriesID, &req)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Reservation series updated successfully",
+		"series":  series,
+	})
+}
+
+// CancelSeries godoc
+// @Summary Cancel a reservation series
+// @Description Cancel every upcoming occurrence; a paid series is refunded per occurrence according to the court's cancellation policy
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Series ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /reservations/series/{id}/cancel [put]
+func (h *ReservationSeriesHandler) CancelSeries(c *gin.Context) {
+	seriesID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	refund, err := h.seriesService.CancelSeries(c.Request.Context(), c.GetUint("userID"), seriesID)
+	if err != nil {
+		respondSeriesError(c, err)
+		return
+	}
+
+	response := gin.H{"message": "Reservation series cancelled successfully"}
+	if refund != nil {
+		response["refund"] = refund
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func respondSeriesError(c *gin.Context, err error) {
+	var conflictErr *services.SeriesConflictError
+	switch {
+	case errors.As(err, &conflictErr):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "conflicts": conflictErr.Conflicts})
+	case errors.Is(err, services.ErrSeriesNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotSeriesOwner),
+		errors.Is(err, services.ErrBookingRestricted):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSlotTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
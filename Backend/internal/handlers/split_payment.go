@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SplitPaymentHandler struct {
+	splitPaymentService services.SplitPaymentService
+}
+
+func NewSplitPaymentHandler(splitPaymentService services.SplitPaymentService) *SplitPaymentHandler {
+	return &SplitPaymentHandler{splitPaymentService: splitPaymentService}
+}
+
+// SplitReservation godoc
+// @Summary Split a reservation between players
+// @Description Invite registered players by user ID or email to share a pending reservation. Each player pays an equal share with POST /payments before the deadline; after it the organiser pays the remainder. The slot stays held until the reservation is fully paid
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Param request body models.SplitReservationRequest true "Players and optional deadline"
+// @Success 201 {object} models.SplitPaymentResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /reservations/{id}/split [post]
+func (h *SplitPaymentHandler) SplitReservation(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req models.SplitReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	split, err := h.splitPaymentService.SplitReservation(c.Request.Context(), c.GetUint("userID"), reservationID, &req)
+	if err != nil {
+		respondSplitPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, split)
+}
+
+// GetSplit godoc
+// @Summary Get the payment progress of a split reservation
+// @Description Show every player's share and whether it is paid, to the organiser and the invited players
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} models.SplitPaymentResponse
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /reservations/{id}/split [get]
+func (h *SplitPaymentHandler) GetSplit(c *gin.Context) {
+	reservationID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	split, err := h.splitPaymentService.GetSplit(c.Request.Context(), c.GetUint("userID"), reservationID)
+	if err != nil {
+		respondSplitPaymentError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, split)
+}
+
+// GetUserSplits godoc
+// @Summary Get user split reservations
+// @Description List the split reservations the authenticated user organises or was invited to
+// @Tags reservations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /reservations/splits [get]
+func (h *SplitPaymentHandler) GetUserSplits(c *gin.Context) {
+	splits, err := h.splitPaymentService.GetUserSplits(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"splits": splits,
+		"count":  len(splits),
+	})
+}
+
+func respondSplitPaymentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrReservationNotFound),
+		errors.Is(err, services.ErrNotSplit):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotReservationOwner),
+		errors.Is(err, services.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlreadySplit),
+		errors.Is(err, services.ErrPaymentInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
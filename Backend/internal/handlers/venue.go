@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VenueHandler struct {
+	venueService services.VenueService
+	courtService services.CourtService
+}
+
+func NewVenueHandler(venueService services.VenueService, courtService services.CourtService) *VenueHandler {
+	return &VenueHandler{
+		venueService: venueService,
+		courtService: courtService,
+	}
+}
+
+// GetVenues godoc
+// @Summary Get all venues
+// @Description Get the list of active venues (halls)
+// @Tags venues
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /venues [get]
+func (h *VenueHandler) GetVenues(c *gin.Context) {
+	venues, err := h.venueService.GetVenues(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get venues"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"venues": venues})
+}
+
+// GetVenueByID godoc
+// @Summary Get venue by ID
+// @Tags venues
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /venues/{id} [get]
+func (h *VenueHandler) GetVenueByID(c *gin.Context) {
+	venueID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	venue, err := h.venueService.GetVenueByID(c.Request.Context(), venueID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"venue": venue})
+}
+
+// GetVenueCourts godoc
+// @Summary Get courts of a venue
+// @Tags venues
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /venues/{id}/courts [get]
+func (h *VenueHandler) GetVenueCourts(c *gin.Context) {
+	venueID, ok := h.venueParam(c)
+	if !ok {
+		return
+	}
+
+	courts, err := h.courtService.GetVenueCourts(c.Request.Context(), venueID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get courts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"courts": courts})
+}
+
+// GetVenueAvailableCourts godoc
+// @Summary Get available courts of a venue
+// @Description Get the venue's courts with their available timeslots for a specific date
+// @Tags venues
+// @Produce json
+// @Param id path int true "Venue ID"
+// @Param date query string true "Date in YYYY-MM-DD format"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /venues/{id}/courts/available [get]
+func (h *VenueHandler) GetVenueAvailableCourts(c *gin.Context) {
+	date := c.Query("date")
+	if date == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Date parameter is required"})
+		return
+	}
+
+	venueID, ok := h.venueParam(c)
+	if !ok {
+		return
+	}
+
+	availableCourts, err := h.courtService.GetVenueAvailableCourts(c.Request.Context(), venueID, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get available courts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"venue_id":         venueID,
+		"date":             date,
+		"available_courts": availableCourts,
+	})
+}
+
+// venueParam reads the venue ID from the path and checks that the venue exists.
+func (h *VenueHandler) venueParam(c *gin.Context) (uint, bool) {
+	venueID, ok := parseIDParam(c)
+	if !ok {
+		return 0, false
+	}
+
+	_, err := h.venueService.GetVenueByID(c.Request.Context(), venueID)
+	if errors.Is(err, services.ErrVenueNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Venue not found"})
+		return 0, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return 0, false
+	}
+	return venueID, true
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"backend/internal/models"
+	"backend/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WaitlistHandler struct {
+	waitlistService services.WaitlistService
+}
+
+func NewWaitlistHandler(waitlistService services.WaitlistService) *WaitlistHandler {
+	return &WaitlistHandler{waitlistService: waitlistService}
+}
+
+// JoinWaitlist godoc
+// @Summary Join the waitlist for a booked slot
+// @Description Queue for a court, date and time range that is already booked. When it frees up, the first user in line is offered the slot and it is held for them for the claim window
+// @Tags waitlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.JoinWaitlistRequest true "Court, date and time range"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(c *gin.Context) {
+	var req models.JoinWaitlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input", "details": err.Error()})
+		return
+	}
+
+	entry, err := h.waitlistService.JoinWaitlist(c.Request.Context(), c.GetUint("userID"), &req)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Joined the waitlist",
+		"entry":   entry,
+	})
+}
+
+// GetUserEntries godoc
+// @Summary Get user waitlist entries
+// @Description List the authenticated user's waitlist entries with their place in the queue and any open offer
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /waitlist [get]
+func (h *WaitlistHandler) GetUserEntries(c *gin.Context) {
+	entries, err := h.waitlistService.GetUserEntries(c.Request.Context(), c.GetUint("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
+
+// LeaveWaitlist godoc
+// @Summary Leave the waitlist
+// @Description Remove a waitlist entry; an open offer is declined and passed to the next user in line
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Waitlist entry ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /waitlist/{id} [delete]
+func (h *WaitlistHandler) LeaveWaitlist(c *gin.Context) {
+	entryID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.waitlistService.LeaveWaitlist(c.Request.Context(), c.GetUint("userID"), entryID); err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left the waitlist"})
+}
+
+// ClaimOffer godoc
+// @Summary Claim a waitlist offer
+// @Description Accept the slot offered to this entry. The reservation is then held for the regular payment window; pay it with POST /payments
+// @Tags waitlist
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Waitlist entry ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /waitlist/{id}/claim [post]
+func (h *WaitlistHandler) ClaimOffer(c *gin.Context) {
+	entryID, ok := parseIDParam(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.waitlistService.ClaimOffer(c.Request.Context(), c.GetUint("userID"), entryID)
+	if err != nil {
+		respondWaitlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Offer claimed, complete the payment to confirm the reservation",
+		"reservation": reservation,
+	})
+}
+
+func respondWaitlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrWaitlistEntryNotFound),
+		errors.Is(err, services.ErrCourtNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrBookingRestricted):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSlotAvailable),
+		errors.Is(err, services.ErrAlreadyWaiting),
+		errors.Is(err, services.ErrOfferNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
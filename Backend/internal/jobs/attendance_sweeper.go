@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"backend/internal/services"
+	"context"
+	"log"
+	"time"
+)
+
+// StartAttendanceSweeper periodically settles bookings whose slot has ended: checked-in
+// bookings are completed, confirmed ones nobody checked in for are marked as no-shows.
+func StartAttendanceSweeper(ctx context.Context, checkInService services.CheckInService, interval time.Duration) {
+	runEvery(ctx, "attendance-sweeper", interval, func(ctx context.Context) error {
+		completed, noShows, err := checkInService.CloseFinishedReservations(ctx)
+		if err != nil {
+			return err
+		}
+		if completed > 0 || noShows > 0 {
+			log.Printf("🏸 Closed finished reservations: %d completed, %d no-show(s)", completed, noShows)
+		}
+		return nil
+	})
+}
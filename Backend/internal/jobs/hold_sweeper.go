@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"backend/internal/services"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// StartHoldSweeper periodically expires unpaid reservations whose hold window has passed,
+// releasing their slots for other players, refunds shares paid towards split reservations
+// that expired, and then offers freed slots to the waitlist. Each step runs even when an
+// earlier one fails, so one broken step does not stall the others.
+func StartHoldSweeper(ctx context.Context, reservationService services.ReservationService, splitPaymentService services.SplitPaymentService, waitlistService services.WaitlistService, interval time.Duration) {
+	runEvery(ctx, "hold-sweeper", interval, func(ctx context.Context) error {
+		failed := 0
+
+		expired, err := reservationService.ExpireStaleHolds(ctx)
+		if err != nil {
+			log.Printf("❌ Expiring unpaid reservation holds failed: %v", err)
+			failed++
+		}
+		if expired > 0 {
+			log.Printf("⏰ Expired %d unpaid reservation hold(s)", expired)
+		}
+
+		refunded, err := splitPaymentService.RefundExpiredSplits(ctx)
+		if err != nil {
+			log.Printf("❌ Refunding expired split reservations failed: %v", err)
+			failed++
+		}
+		if refunded > 0 {
+			log.Printf("💸 Refunded %d share(s) of expired split reservation(s)", refunded)
+		}
+
+		offered, err := waitlistService.ProcessWaitlist(ctx)
+		if err != nil {
+			log.Printf("❌ Processing the waitlist failed: %v", err)
+			failed++
+		}
+		if offered > 0 {
+			log.Printf("📣 Offered %d freed slot(s) to the waitlist", offered)
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of 3 step(s) failed", failed)
+		}
+		return nil
+	})
+}
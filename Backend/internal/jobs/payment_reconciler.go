@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"backend/internal/services"
+	"context"
+	"log"
+	"time"
+)
+
+// StartPaymentReconciler periodically re-checks payments that have been pending longer
+// than staleAfter against Midtrans, in case their notification was lost.
+func StartPaymentReconciler(ctx context.Context, midtransService services.MidtransService, interval, staleAfter time.Duration) {
+	runEvery(ctx, "payment-reconciler", interval, func(ctx context.Context) error {
+		updated, err := midtransService.ReconcilePendingPayments(ctx, time.Now().Add(-staleAfter))
+		if err != nil {
+			return err
+		}
+		if updated > 0 {
+			log.Printf("🔁 Reconciled %d pending payment(s) with Midtrans", updated)
+		}
+		return nil
+	})
+}
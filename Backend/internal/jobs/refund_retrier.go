@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"backend/internal/services"
+	"context"
+	"log"
+	"time"
+)
+
+// StartRefundRetrier periodically sends refunds the gateway rejected again, so money owed
+// for a cancelled booking or a late payment is not lost to one failed request. A refund is
+// retried once its last attempt is at least an interval old.
+func StartRefundRetrier(ctx context.Context, midtransService services.MidtransService, interval time.Duration) {
+	runEvery(ctx, "refund-retrier", interval, func(ctx context.Context) error {
+		refunded, err := midtransService.RetryPendingRefunds(ctx, time.Now().Add(-interval))
+		if err != nil {
+			return err
+		}
+		if refunded > 0 {
+			log.Printf("💸 Retried %d pending refund(s) successfully", refunded)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runEvery calls task on every tick of interval until ctx is cancelled.
+func runEvery(ctx context.Context, name string, interval time.Duration, task func(ctx context.Context) error) {
+	if interval <= 0 {
+		log.Printf("⚠️ Job %s disabled (interval %s)", name, interval)
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := task(ctx); err != nil {
+					log.Printf("❌ Job %s failed: %v", name, err)
+				}
+			}
+		}
+	}()
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"backend/internal/repositories"
 	"backend/pkg/utils"
 	"net/http"
 	"strings"
@@ -42,7 +43,50 @@ func AuthMiddleware() gin.HandlerFunc {
 		// Set user info in context for later use
 		c.Set("userID", claims.UserID)
 		c.Set("userEmail", claims.Email)
+		c.Set("userRole", claims.Role)
 
 		c.Next()
 	}
 }
+
+// RequireRole must run after AuthMiddleware; it allows the request only when the user's
+// current role is one of roles. The role and venue are read from the users table rather
+// than the token, so a demotion or venue change applies immediately instead of when the
+// token expires; services scope staff by the venue they read the same way.
+func RequireRole(userRepo repositories.UserRepository, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := userRepo.GetUserByID(c.Request.Context(), c.GetUint("userID"))
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "User not found",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("userRole", user.Role)
+		if user.Role == "staff" && user.VenueID == nil {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Staff account is not assigned to a venue",
+			})
+			c.Abort()
+			return
+		}
+		for _, allowed := range roles {
+			if user.Role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "Insufficient permissions",
+		})
+		c.Abort()
+	}
+}
+
+// AdminMiddleware must run after AuthMiddleware; it only lets admins through.
+func AdminMiddleware(userRepo repositories.UserRepository) gin.HandlerFunc {
+	return RequireRole(userRepo, "admin")
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditLog records who changed what through the admin API.
+type AuditLog struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ActorID    uint      `json:"actor_id" gorm:"not null;index"`
+	Action     string    `json:"action" gorm:"not null"`                             // court.create, closure.delete, ...
+	EntityType string    `json:"entity_type" gorm:"not null;index:idx_audit_entity"` // court, closure, ...
+	EntityID   uint      `json:"entity_id" gorm:"index:idx_audit_entity"`
+	Before     string    `json:"before,omitempty" gorm:"type:text"` // JSON snapshot before the change
+	After      string    `json:"after,omitempty" gorm:"type:text"`  // JSON snapshot after the change
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+
+	// Relationship
+	Actor User `json:"actor" gorm:"foreignKey:ActorID"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+)
+
+// Closure blocks bookings between StartAt and EndAt on one court, on every court of a
+// venue when only VenueID is set, or on every court of every venue when both are nil
+// (e.g. a public holiday).
+type Closure struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CourtID   *uint     `json:"court_id,omitempty" gorm:"index"`
+	VenueID   *uint     `json:"venue_id,omitempty" gorm:"index"`
+	StartAt   time.Time `json:"start_at" gorm:"type:timestamptz;not null;index"`
+	EndAt     time.Time `json:"end_at" gorm:"type:timestamptz;not null"`
+	Reason    string    `json:"reason" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relationship
+	Court *Court `json:"court,omitempty" gorm:"foreignKey:CourtID"`
+	Venue *Venue `json:"venue,omitempty" gorm:"foreignKey:VenueID"`
+}
+
+type CreateClosureRequest struct {
+	CourtID *uint     `json:"court_id"` // Omit to close every court of the venue
+	VenueID *uint     `json:"venue_id"` // Omit both to close every venue
+	StartAt time.Time `json:"start_at" binding:"required"`
+	EndAt   time.Time `json:"end_at" binding:"required,gtfield=StartAt"`
+	Reason  string    `json:"reason" binding:"required"`
+}
@@ -1,22 +1,132 @@
 package models
 
 import (
+	"backend/pkg/money"
 	"time"
 )
 
 type Court struct {
-	ID           uint      `json:"id" gorm:"primaryKey"`
-	Name         string    `json:"name" gorm:"not null"`
-	Status       string    `json:"status" gorm:"default:active"`
-	Location     string    `json:"location" gorm:"not null"`
-	PricePerHour float64   `json:"price_per_hour" gorm:"not null"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	VenueID      *uint        `json:"venue_id" gorm:"index"`
+	Name         string       `json:"name" gorm:"not null"`
+	Status       string       `json:"status" gorm:"default:active"`
+	Location     string       `json:"location" gorm:"not null"`
+	PricePerHour money.Amount `json:"price_per_hour" gorm:"not null"`
+	CreatedAt    time.Time    `json:"created_at"`
+
+	// Descriptive details shown on the court page
+	Description string   `json:"description" gorm:"type:text"`
+	Environment string   `json:"environment" gorm:"default:indoor"` // indoor, outdoor
+	FloorType   string   `json:"floor_type"`                        // synthetic, wood, vinyl, cement
+	Lighting    string   `json:"lighting"`                          // e.g. LED, halogen
+	HasAC       bool     `json:"has_ac"`
+	Capacity    int      `json:"capacity" gorm:"default:4"` // Players on court
+	Photos      []string `json:"photos" gorm:"serializer:json;type:text"`
+
+	// SlotMinutes is the booking granularity (30, 60 or 90); bookings are whole multiples of it
+	SlotMinutes int `json:"slot_minutes" gorm:"default:60"`
+
+	// Cancellation policy: full refund up to FullRefundHours before start,
+	// PartialRefundPercent up to PartialRefundHours before start, nothing after
+	FullRefundHours      int `json:"full_refund_hours" gorm:"default:24"`
+	PartialRefundHours   int `json:"partial_refund_hours" gorm:"default:2"`
+	PartialRefundPercent int `json:"partial_refund_percent" gorm:"default:50"`
+
+	// Reschedule policy: customers can move a booking until RescheduleCutoffHours before start
+	RescheduleCutoffHours int `json:"reschedule_cutoff_hours" gorm:"default:12"`
+
+	// Relationship
+	Venue *Venue `json:"venue,omitempty" gorm:"foreignKey:VenueID"`
 }
 
 type CourtResponse struct {
-	ID           uint    `json:"id"`
-	Name         string  `json:"name"`
-	Status       string  `json:"status"`
-	Location     string  `json:"location"`
-	PricePerHour float64 `json:"price_per_hour"`
+	ID           uint         `json:"id"`
+	VenueID      *uint        `json:"venue_id"`
+	VenueName    string       `json:"venue_name,omitempty"`
+	Name         string       `json:"name"`
+	Status       string       `json:"status"`
+	Location     string       `json:"location"`
+	PricePerHour money.Amount `json:"price_per_hour"`
+	SlotMinutes  int          `json:"slot_minutes"`
+	Description  string       `json:"description"`
+	Environment  string       `json:"environment"`
+	FloorType    string       `json:"floor_type"`
+	Lighting     string       `json:"lighting"`
+	HasAC        bool         `json:"has_ac"`
+	Capacity     int          `json:"capacity"`
+	Photos       []string     `json:"photos"`
+}
+
+// CourtDetailResponse is the full court page: the court, its cancellation policy, pricing
+// rules still in effect, the weekly schedule and today's slots.
+type CourtDetailResponse struct {
+	CourtResponse
+	FullRefundHours       int                   `json:"full_refund_hours"`
+	PartialRefundHours    int                   `json:"partial_refund_hours"`
+	PartialRefundPercent  int                   `json:"partial_refund_percent"`
+	RescheduleCutoffHours int                   `json:"reschedule_cutoff_hours"`
+	PricingRules          []PricingRule         `json:"pricing_rules"`
+	OperatingHours        []CourtOperatingHours `json:"operating_hours"`
+	Today                 AvailableSlotResponse `json:"today"`
+}
+
+// CreateCourtRequest is the admin payload for a new court. Hours are optional;
+// without them the default 07:00-21:00 schedule applies.
+type CreateCourtRequest struct {
+	VenueID               uint                    `json:"venue_id" binding:"required"`
+	Name                  string                  `json:"name" binding:"required,max=100"`
+	Location              string                  `json:"location" binding:"required,max=255"`
+	PricePerHour          money.Amount            `json:"price_per_hour" binding:"required,min=1"`
+	Status                string                  `json:"status" binding:"omitempty,oneof=active maintenance inactive"`
+	SlotMinutes           int                     `json:"slot_minutes" binding:"omitempty,oneof=30 60 90"`
+	FullRefundHours       *int                    `json:"full_refund_hours" binding:"omitempty,min=0"`
+	PartialRefundHours    *int                    `json:"partial_refund_hours" binding:"omitempty,min=0"`
+	PartialRefundPercent  *int                    `json:"partial_refund_percent" binding:"omitempty,min=0,max=100"`
+	RescheduleCutoffHours *int                    `json:"reschedule_cutoff_hours" binding:"omitempty,min=0"`
+	Hours                 []OperatingHoursRequest `json:"hours" binding:"omitempty,dive"`
+	Description           string                  `json:"description"`
+	Environment           string                  `json:"environment" binding:"omitempty,oneof=indoor outdoor"`
+	FloorType             string                  `json:"floor_type" binding:"max=50"`
+	Lighting              string                  `json:"lighting" binding:"max=50"`
+	HasAC                 bool                    `json:"has_ac"`
+	Capacity              int                     `json:"capacity" binding:"omitempty,min=1"`
+	Photos                []string                `json:"photos" binding:"omitempty,dive,url"`
+}
+
+// UpdateCourtRequest changes only the fields that are present.
+type UpdateCourtRequest struct {
+	VenueID               *uint         `json:"venue_id"`
+	Name                  *string       `json:"name" binding:"omitempty,min=1,max=100"`
+	Location              *string       `json:"location" binding:"omitempty,min=1,max=255"`
+	PricePerHour          *money.Amount `json:"price_per_hour" binding:"omitempty,min=1"`
+	Status                *string       `json:"status" binding:"omitempty,oneof=active maintenance inactive"`
+	SlotMinutes           *int          `json:"slot_minutes" binding:"omitempty,oneof=30 60 90"`
+	FullRefundHours       *int          `json:"full_refund_hours" binding:"omitempty,min=0"`
+	PartialRefundHours    *int          `json:"partial_refund_hours" binding:"omitempty,min=0"`
+	PartialRefundPercent  *int          `json:"partial_refund_percent" binding:"omitempty,min=0,max=100"`
+	RescheduleCutoffHours *int          `json:"reschedule_cutoff_hours" binding:"omitempty,min=0"`
+	Description           *string       `json:"description"`
+	Environment           *string       `json:"environment" binding:"omitempty,oneof=indoor outdoor"`
+	FloorType             *string       `json:"floor_type" binding:"omitempty,max=50"`
+	Lighting              *string       `json:"lighting" binding:"omitempty,max=50"`
+	HasAC                 *bool         `json:"has_ac"`
+	Capacity              *int          `json:"capacity" binding:"omitempty,min=1"`
+	Photos                []string      `json:"photos" binding:"omitempty,dive,url"` // Replaces all photos when present
+}
+
+type OperatingHoursRequest struct {
+	Weekday   *int   `json:"weekday" binding:"required,min=0,max=6"` // 0 = Sunday ... 6 = Saturday
+	OpenTime  string `json:"open_time"`
+	CloseTime string `json:"close_time"`
+	Closed    bool   `json:"closed"`
+}
+
+type SetOperatingHoursRequest struct {
+	Hours []OperatingHoursRequest `json:"hours" binding:"required,dive"`
+}
+
+// AdminCourtResponse is a court with its weekly operating hours.
+type AdminCourtResponse struct {
+	Court
+	Hours []CourtOperatingHours `json:"hours"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// CourtOperatingHours is a court's regular schedule for one day of the week.
+type CourtOperatingHours struct {
+	ID        uint   `json:"id" gorm:"primaryKey"`
+	CourtID   uint   `json:"court_id" gorm:"not null;uniqueIndex:idx_court_weekday"`
+	Weekday   int    `json:"weekday" gorm:"not null;uniqueIndex:idx_court_weekday"` // 0 = Sunday ... 6 = Saturday
+	OpenTime  string `json:"open_time" gorm:"not null"`                             // "HH:MM"
+	CloseTime string `json:"close_time" gorm:"not null"`                            // "HH:MM", "24:00" for midnight
+	Closed    bool   `json:"closed" gorm:"default:false"`
+}
+
+// CourtSpecialHours replaces the weekly schedule on a specific date.
+type CourtSpecialHours struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CourtID   uint      `json:"court_id" gorm:"not null;uniqueIndex:idx_court_special_date"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_court_special_date"`
+	OpenTime  string    `json:"open_time"`
+	CloseTime string    `json:"close_time"`
+	Closed    bool      `json:"closed" gorm:"default:false"`
+	Note      string    `json:"note"`
+}
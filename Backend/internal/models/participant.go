@@ -0,0 +1,62 @@
+package models
+
+import (
+	"backend/pkg/money"
+	"time"
+)
+
+// ReservationParticipant is one player sharing the court fee of a split reservation. The
+// organiser (the booking user) is a participant too, and covers every share still unpaid
+// at the deadline.
+type ReservationParticipant struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	ReservationID uint         `json:"reservation_id" gorm:"not null;uniqueIndex:idx_participants_reservation_user"`
+	UserID        uint         `json:"user_id" gorm:"not null;uniqueIndex:idx_participants_reservation_user;index"`
+	ShareAmount   money.Amount `json:"share_amount" gorm:"not null"`
+	Status        string       `json:"status" gorm:"default:pending"` // pending, paid, covered (paid by the organiser)
+	PaidAt        *time.Time   `json:"paid_at,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+
+	// Relationships
+	User        User        `json:"-" gorm:"foreignKey:UserID"`
+	Reservation Reservation `json:"-" gorm:"foreignKey:ReservationID"`
+}
+
+// SplitReservationRequest shares a pending reservation's court fee equally between the
+// organiser and the invited players. Each player pays their share before the deadline.
+type SplitReservationRequest struct {
+	Participants []ParticipantInvite `json:"participants" binding:"required,min=1,max=7,dive"`
+	Deadline     *time.Time          `json:"deadline"` // RFC 3339; defaults to the split payment window from now
+}
+
+// ParticipantInvite names a registered player by user_id or email.
+type ParticipantInvite struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email" binding:"omitempty,email"`
+}
+
+type ParticipantResponse struct {
+	ID          uint         `json:"id"`
+	UserID      uint         `json:"user_id"`
+	Name        string       `json:"name"`
+	Email       string       `json:"email"`
+	Organiser   bool         `json:"organiser"`
+	ShareAmount money.Amount `json:"share_amount"`
+	Status      string       `json:"status"`
+	PaidAt      *time.Time   `json:"paid_at,omitempty"`
+}
+
+// SplitPaymentResponse is the payment progress of a split reservation.
+type SplitPaymentResponse struct {
+	ReservationID     uint                  `json:"reservation_id"`
+	OrganiserID       uint                  `json:"organiser_id"`
+	CourtName         string                `json:"court_name"`
+	ReservationDate   string                `json:"reservation_date"`
+	TimeSlot          string                `json:"time_slot"`
+	ReservationStatus string                `json:"reservation_status"`
+	TotalAmount       money.Amount          `json:"total_amount"`
+	PaidAmount        money.Amount          `json:"paid_amount"`
+	Remaining         money.Amount          `json:"remaining"`
+	Deadline          time.Time             `json:"deadline"`
+	Participants      []ParticipantResponse `json:"participants"`
+}
@@ -1,43 +1,66 @@
 package models
 
 import (
+	"backend/pkg/money"
 	"time"
 )
 
 type Payment struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	ReservationID   uint      `json:"reservation_id" gorm:"not null"`
-	Amount          float64   `json:"amount" gorm:"not null"`
-	Status          string    `json:"status" gorm:"default:pending"`
-	PaymentMethod   string    `json:"payment_method"`
-	MidtransOrderID string    `json:"midtrans_order_id"`
-	VaNumber        string    `json:"va_number"`
-	VaBank          string    `json:"va_bank"`
-	PaymentTime     time.Time `json:"payment_time"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	ReservationID   uint         `json:"reservation_id" gorm:"not null"`
+	SeriesID        *uint        `json:"series_id,omitempty" gorm:"index"` // Set when paying a whole series; ReservationID is then its first occurrence
+	Amount          money.Amount `json:"amount" gorm:"not null"`
+	Fee             money.Amount `json:"fee" gorm:"default:0"` // Channel fee, included in Amount
+	Currency        string       `json:"currency" gorm:"default:IDR"`
+	Status          string       `json:"status" gorm:"default:pending"`
+	Purpose         string       `json:"purpose" gorm:"default:booking"`        // booking, top_up (price difference after a reschedule), share, remainder (shares the organiser covers)
+	ParticipantID   *uint        `json:"participant_id,omitempty" gorm:"index"` // Player whose share this pays
+	PaymentMethod   string       `json:"payment_method"`
+	MidtransOrderID string       `json:"midtrans_order_id"`
+	VaNumber        string       `json:"va_number"`
+	VaBank          string       `json:"va_bank"`
+	BillerCode      string       `json:"biller_code,omitempty"`
+	SnapToken       string       `json:"snap_token,omitempty"`
+	RedirectURL     string       `json:"redirect_url,omitempty"`
+	PaymentTime     time.Time    `json:"payment_time"`
+	ExpiresAt       *time.Time   `json:"expires_at,omitempty"` // When the Midtrans order lapses with the hold; nil for the gateway default
+	CreatedAt       time.Time    `json:"created_at"`
+
+	// Last Midtrans transaction state applied, used to skip duplicate notifications
+	TransactionID     string `json:"transaction_id" gorm:"index"`
+	TransactionStatus string `json:"transaction_status"`
 
 	// Relationship
 	Reservation Reservation `json:"reservation" gorm:"foreignKey:ReservationID"`
+	Refunds     []Refund    `json:"refunds,omitempty" gorm:"foreignKey:PaymentID"`
 }
 
 type CreatePaymentRequest struct {
 	ReservationID uint   `json:"reservation_id" binding:"required"`
 	PaymentMethod string `json:"payment_method" binding:"required"`
+	Bank          string `json:"bank"` // VA bank for bank_transfer, defaults to bca
+}
+
+// CreateSeriesPaymentRequest pays for every occurrence of a recurring booking at once.
+type CreateSeriesPaymentRequest struct {
+	SeriesID      uint   `json:"series_id" binding:"required"`
+	PaymentMethod string `json:"payment_method" binding:"required"`
+	Bank          string `json:"bank"` // VA bank for bank_transfer, defaults to bca
 }
 
 type PaymentResponse struct {
-	ID              uint      `json:"id"`
-	ReservationID   uint      `json:"reservation_id"`
-	Amount          float64   `json:"amount"`
-	Status          string    `json:"status"`
-	PaymentMethod   string    `json:"payment_method"`
-	MidtransOrderID string    `json:"midtrans_order_id"`
-	VaNumber        string    `json:"va_number,omitempty"`
-	VaBank          string    `json:"va_bank,omitempty"`
-	SnapToken       string    `json:"snap_token,omitempty"`
-	RedirectURL     string    `json:"redirect_url,omitempty"`
-	PaymentTime     time.Time `json:"payment_time"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uint         `json:"id"`
+	ReservationID   uint         `json:"reservation_id"`
+	Amount          money.Amount `json:"amount"`
+	Status          string       `json:"status"`
+	PaymentMethod   string       `json:"payment_method"`
+	MidtransOrderID string       `json:"midtrans_order_id"`
+	VaNumber        string       `json:"va_number,omitempty"`
+	VaBank          string       `json:"va_bank,omitempty"`
+	SnapToken       string       `json:"snap_token,omitempty"`
+	RedirectURL     string       `json:"redirect_url,omitempty"`
+	PaymentTime     time.Time    `json:"payment_time"`
+	CreatedAt       time.Time    `json:"created_at"`
 }
 
 type MidtransNotification struct {
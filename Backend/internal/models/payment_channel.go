@@ -0,0 +1,31 @@
+package models
+
+import (
+	"backend/pkg/money"
+	"time"
+)
+
+// PaymentChannel is an entry of the payment method catalogue. The fee is charged to the
+// customer on top of the reservation amount: FeeFlat + FeePercent of the amount.
+type PaymentChannel struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	Code          string       `json:"code" gorm:"uniqueIndex;not null"` // bca_va, gopay, qris, ...
+	Name          string       `json:"name" gorm:"not null"`             // Label for the checkout page
+	PaymentMethod string       `json:"payment_method" gorm:"not null"`   // bank_transfer, gopay, shopeepay, qris, credit_card
+	Bank          string       `json:"bank,omitempty"`                   // VA bank for bank_transfer
+	FeeFlat       money.Amount `json:"fee_flat" gorm:"default:0"`        // Fixed fee per transaction
+	FeePercent    float64      `json:"fee_percent" gorm:"default:0"`     // Fee as a percentage of the amount
+	Enabled       bool         `json:"enabled" gorm:"default:true"`      // Disabled channels are hidden and rejected
+	SortOrder     int          `json:"sort_order" gorm:"default:0"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+type PaymentChannelResponse struct {
+	Code          string       `json:"code"`
+	Name          string       `json:"name"`
+	PaymentMethod string       `json:"payment_method"`
+	Bank          string       `json:"bank,omitempty"`
+	FeeFlat       money.Amount `json:"fee_flat"`
+	FeePercent    float64      `json:"fee_percent"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// PaymentNotification is an audit record of a webhook call received from Midtrans.
+type PaymentNotification struct {
+	ID                uint      `json:"id" gorm:"primaryKey"`
+	OrderID           string    `json:"order_id" gorm:"index"`
+	TransactionStatus string    `json:"transaction_status"`
+	RawBody           string    `json:"raw_body" gorm:"type:text"`
+	Headers           string    `json:"headers" gorm:"type:text"`
+	SignatureValid    *bool     `json:"signature_valid"`
+	Outcome           string    `json:"outcome"` // processed, test, rejected, failed
+	Error             string    `json:"error,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
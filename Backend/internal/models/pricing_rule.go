@@ -0,0 +1,66 @@
+package models
+
+import (
+	"backend/pkg/money"
+	"time"
+)
+
+// PricingRule overrides a court's PricePerHour. Empty fields match everything, so a rule
+// can be a time-of-day band, a weekday/weekend/holiday rate, a date-range override or a
+// combination. When several rules match, the highest Priority wins.
+type PricingRule struct {
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	CourtID      uint         `json:"court_id" gorm:"not null;index"`
+	Name         string       `json:"name" gorm:"not null"`
+	DayType      string       `json:"day_type"`   // "", weekday, weekend, holiday
+	StartTime    string       `json:"start_time"` // "HH:MM", empty for the whole day
+	EndTime      string       `json:"end_time"`   // "HH:MM", "24:00" for end of day
+	StartDate    *time.Time   `json:"start_date,omitempty" gorm:"type:date"`
+	EndDate      *time.Time   `json:"end_date,omitempty" gorm:"type:date"`
+	PricePerHour money.Amount `json:"price_per_hour" gorm:"not null"`
+	Priority     int          `json:"priority" gorm:"default:0"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// Holiday marks a date priced with holiday (and weekend) rules.
+type Holiday struct {
+	ID   uint      `json:"id" gorm:"primaryKey"`
+	Date time.Time `json:"date" gorm:"type:date;uniqueIndex;not null"`
+	Name string    `json:"name"`
+}
+
+type CreatePricingRuleRequest struct {
+	CourtID      uint         `json:"court_id" binding:"required"`
+	Name         string       `json:"name" binding:"required,max=100"`
+	DayType      string       `json:"day_type" binding:"omitempty,oneof=weekday weekend holiday"` // Omit for every day
+	StartTime    string       `json:"start_time"`                                                 // "HH:MM"; omit both times for the whole day
+	EndTime      string       `json:"end_time"`                                                   // "HH:MM", "24:00" for end of day
+	StartDate    string       `json:"start_date"`                                                 // YYYY-MM-DD; omit for no start
+	EndDate      string       `json:"end_date"`                                                   // YYYY-MM-DD; omit for no end
+	PricePerHour money.Amount `json:"price_per_hour" binding:"min=0"`
+	Priority     int          `json:"priority"`
+}
+
+// UpdatePricingRuleRequest changes only the fields that are present; an empty string
+// clears the day type, time band or a date.
+type UpdatePricingRuleRequest struct {
+	Name         *string       `json:"name" binding:"omitempty,min=1,max=100"`
+	DayType      *string       `json:"day_type" binding:"omitempty,oneof='' weekday weekend holiday"`
+	StartTime    *string       `json:"start_time"`
+	EndTime      *string       `json:"end_time"`
+	StartDate    *string       `json:"start_date"`
+	EndDate      *string       `json:"end_date"`
+	PricePerHour *money.Amount `json:"price_per_hour" binding:"omitempty,min=0"`
+	Priority     *int          `json:"priority"`
+}
+
+type CreateHolidayRequest struct {
+	Date string `json:"date" binding:"required"` // YYYY-MM-DD
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// UpdateHolidayRequest changes only the fields that are present.
+type UpdateHolidayRequest struct {
+	Date *string `json:"date"`
+	Name *string `json:"name" binding:"omitempty,min=1,max=100"`
+}
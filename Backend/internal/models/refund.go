@@ -0,0 +1,25 @@
+package models
+
+import (
+	"backend/pkg/money"
+	"time"
+)
+
+// Refund records money returned to the customer for a payment. A refund the gateway has
+// not accepted yet stays pending and is retried with the same key.
+type Refund struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	PaymentID     uint         `json:"payment_id" gorm:"not null;index"`
+	ReservationID uint         `json:"reservation_id" gorm:"not null"`
+	Amount        money.Amount `json:"amount" gorm:"not null"`
+	Reason        string       `json:"reason"`
+	Status        string       `json:"status" gorm:"default:pending"` // pending, succeeded, failed (gave up after repeated attempts)
+	RefundKey     string       `json:"refund_key" gorm:"uniqueIndex"`
+	Attempts      int          `json:"attempts" gorm:"default:0"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+
+	// Relationship
+	Payment *Payment `json:"-" gorm:"foreignKey:PaymentID"`
+}
@@ -1,43 +1,166 @@
 package models
 
 import (
+	"backend/pkg/money"
 	"time"
 )
 
 type Reservation struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	UserID          uint      `json:"user_id" gorm:"not null"`
-	CourtID         uint      `json:"court_id" gorm:"not null"`
-	ReservationDate time.Time `json:"reservation_date" gorm:"type:date;not null"`
-	TimeSlot        string    `json:"time_slot" gorm:"not null"`
-	DurationHours   int       `json:"duration_hours" gorm:"default:1"`
-	TotalAmount     float64   `json:"total_amount" gorm:"not null"`
-	Status          string    `json:"status" gorm:"default:pending"`
+	ID              uint         `json:"id" gorm:"primaryKey"`
+	UserID          uint         `json:"user_id" gorm:"not null"`
+	CourtID         uint         `json:"court_id" gorm:"not null"`
+	ReservationDate time.Time    `json:"reservation_date" gorm:"type:date;not null"`
+	TimeSlot        string       `json:"time_slot" gorm:"not null"`
+	StartAt         time.Time    `json:"start_at" gorm:"type:timestamptz;index"`
+	EndAt           time.Time    `json:"end_at" gorm:"type:timestamptz"`
+	DurationHours   int          `json:"duration_hours" gorm:"default:1"` // Whole hours, kept for older clients
+	DurationMinutes int          `json:"duration_minutes"`
+	TotalAmount     money.Amount `json:"total_amount" gorm:"not null"`
+	Status          string       `json:"status" gorm:"default:pending"` // pending, confirmed, checked_in, completed, no_show, cancelled, expired
+
+	// AmountDue is the top-up still owed after rescheduling a paid booking to a pricier slot
+	AmountDue money.Amount `json:"amount_due" gorm:"default:0"`
+
+	// HoldExpiresAt is when an unpaid (pending) reservation stops blocking the court
+	HoldExpiresAt *time.Time `json:"hold_expires_at,omitempty" gorm:"index"`
+
+	// ClosureID flags a booking overlapped by a closure; it needs rebooking or a refund
+	ClosureID *uint `json:"closure_id,omitempty" gorm:"index"`
+
+	// Front desk bookings: a walk-in guest without an account, and the staff member who booked
+	GuestName   string `json:"guest_name,omitempty"`
+	GuestPhone  string `json:"guest_phone,omitempty"`
+	CreatedByID *uint  `json:"created_by_id,omitempty"`
+
+	// SeriesID links an occurrence of a recurring booking to its series
+	SeriesID *uint `json:"series_id,omitempty" gorm:"index"`
+
+	// SplitDeadline is set when players share the court fee; unpaid shares fall to the organiser after it
+	SplitDeadline *time.Time `json:"split_deadline,omitempty"`
+
+	// Check-in at the front desk, and the staff member who scanned the code
+	CheckedInAt   *time.Time `json:"checked_in_at,omitempty"`
+	CheckedInByID *uint      `json:"checked_in_by_id,omitempty"`
 
 	CreatedAt time.Time `json:"created_at"`
 
 	// Relationships
-	User  User  `json:"user" gorm:"foreignKey:UserID"`
-	Court Court `json:"court" gorm:"foreignKey:CourtID"`
+	User       User                   `json:"user" gorm:"foreignKey:UserID"`
+	Court      Court                  `json:"court" gorm:"foreignKey:CourtID"`
+	PriceLines []ReservationPriceLine `json:"price_breakdown,omitempty" gorm:"foreignKey:ReservationID"`
 }
 
+// ReservationPriceLine is one priced segment of a reservation, as quoted when it was booked.
+type ReservationPriceLine struct {
+	ID            uint         `json:"-" gorm:"primaryKey"`
+	ReservationID uint         `json:"-" gorm:"not null;index"`
+	TimeSlot      string       `json:"time_slot" gorm:"not null"`
+	PricePerHour  money.Amount `json:"price_per_hour" gorm:"not null"`
+	Amount        money.Amount `json:"amount" gorm:"not null"`
+	PricingRuleID *uint        `json:"pricing_rule_id,omitempty"`
+	RuleName      string       `json:"rule_name,omitempty"` // Empty when the court's base price applied
+}
+
+// CreateReservationRequest accepts either a time_slot ("HH:MM-HH:MM"), a start_time
+// and end_time, or a start_time and duration_minutes (or duration_hours).
 type CreateReservationRequest struct {
-	CourtID  uint   `json:"court_id" binding:"required"`
-	Date     string `json:"date" binding:"required"`
-	TimeSlot string `json:"time_slot" binding:"required"`
+	CourtID         uint   `json:"court_id" binding:"required"`
+	Date            string `json:"date" binding:"required"`
+	TimeSlot        string `json:"time_slot"`
+	StartTime       string `json:"start_time"`
+	EndTime         string `json:"end_time"`
+	DurationHours   int    `json:"duration_hours" binding:"omitempty,min=1"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1"`
+}
+
+// AdminCreateReservationRequest books on behalf of a registered customer (user_id) or a
+// walk-in guest (guest_name). With cash_paid the booking is confirmed straight away.
+type AdminCreateReservationRequest struct {
+	CreateReservationRequest
+	UserID     *uint  `json:"user_id"`
+	GuestName  string `json:"guest_name" binding:"max=100"`
+	GuestPhone string `json:"guest_phone" binding:"max=30"`
+	CashPaid   bool   `json:"cash_paid"`
+}
+
+// MoveReservationRequest moves a booking to another start time, date or court.
+// The duration and the price paid stay the same.
+type MoveReservationRequest struct {
+	CourtID   uint   `json:"court_id"` // Defaults to the current court
+	Date      string `json:"date" binding:"required"`
+	StartTime string `json:"start_time" binding:"required"`
+	Reason    string `json:"reason" binding:"max=255"`
+}
+
+// RescheduleReservationRequest moves a customer's booking to another slot, and optionally
+// another court. The time range is given like a booking; a start_time alone keeps the
+// current duration. The booking is re-priced for the new slot.
+type RescheduleReservationRequest struct {
+	CourtID         uint   `json:"court_id"` // Defaults to the current court
+	Date            string `json:"date" binding:"required"`
+	TimeSlot        string `json:"time_slot"`
+	StartTime       string `json:"start_time"`
+	EndTime         string `json:"end_time"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=1"`
+}
+
+// ForceCancelReservationRequest cancels a booking regardless of the cancellation policy.
+type ForceCancelReservationRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+	Refund bool   `json:"refund"` // Refund the booking amount in full; channel fees are kept
 }
 
 type ReservationResponse struct {
-	ID              uint      `json:"id"`
-	UserID          uint      `json:"user_id"`
-	CourtID         uint      `json:"court_id"`
-	CourtName       string    `json:"court_name"`
-	ReservationDate string    `json:"reservation_date"`
-	TimeSlot        string    `json:"time_slot"`
-	DurationHours   int       `json:"duration_hours"`
-	TotalAmount     float64   `json:"total_amount"`
-	Status          string    `json:"status"`
-	CreatedAt       time.Time `json:"created_at"`
+	ID              uint         `json:"id"`
+	UserID          uint         `json:"user_id"`
+	CourtID         uint         `json:"court_id"`
+	CourtName       string       `json:"court_name"`
+	ReservationDate string       `json:"reservation_date"`
+	TimeSlot        string       `json:"time_slot"`
+	DurationHours   int          `json:"duration_hours"`
+	DurationMinutes int          `json:"duration_minutes"`
+	TotalAmount     money.Amount `json:"total_amount"`
+	AmountDue       money.Amount `json:"amount_due,omitempty"`
+	Status          string       `json:"status"`
+	HoldExpiresAt   *time.Time   `json:"hold_expires_at,omitempty"`
+	ClosureID       *uint        `json:"closure_id,omitempty"`
+	SeriesID        *uint        `json:"series_id,omitempty"`
+	SplitDeadline   *time.Time   `json:"split_deadline,omitempty"`
+	CheckedInAt     *time.Time   `json:"checked_in_at,omitempty"`
+	CreatedAt       time.Time    `json:"created_at"`
+
+	PriceBreakdown []ReservationPriceLine `json:"price_breakdown,omitempty"`
+}
+
+// AdminReservationResponse adds who the booking is for to a reservation.
+type AdminReservationResponse struct {
+	ReservationResponse
+	CustomerName  string `json:"customer_name"`
+	CustomerEmail string `json:"customer_email"`
+	GuestName     string `json:"guest_name,omitempty"`
+	GuestPhone    string `json:"guest_phone,omitempty"`
+	CreatedByID   *uint  `json:"created_by_id,omitempty"`
+}
+
+// CheckInCodeResponse is the signed code a customer shows as a QR code at the front desk.
+type CheckInCodeResponse struct {
+	ReservationID uint      `json:"reservation_id"`
+	Code          string    `json:"code"`
+	ValidFrom     time.Time `json:"valid_from"`
+	ValidUntil    time.Time `json:"valid_until"`
+}
+
+// CheckInRequest is the payload scanned from a customer's QR code.
+type CheckInRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// NoShowStatus is how many bookings a user missed recently and whether that blocks new bookings.
+type NoShowStatus struct {
+	Count      int64 `json:"count"`
+	Limit      int   `json:"limit"` // 0 when no-shows never restrict booking
+	WindowDays int   `json:"window_days"`
+	Restricted bool  `json:"restricted"`
 }
 
 type CheckAvailabilityRequest struct {
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"backend/pkg/money"
+	"time"
+)
+
+// ReservationSeries is a recurring booking: the same time on the same courts every week
+// or every other week. Each occurrence is an ordinary reservation with SeriesID set, and
+// the whole series is paid with one payment.
+type ReservationSeries struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	UserID      uint         `json:"user_id" gorm:"not null;index"`
+	CourtIDs    []uint       `json:"court_ids" gorm:"serializer:json;type:text;not null"`
+	Frequency   string       `json:"frequency" gorm:"not null"` // weekly, biweekly
+	StartDate   time.Time    `json:"start_date" gorm:"type:date;not null"`
+	EndDate     time.Time    `json:"end_date" gorm:"type:date;not null"` // Date of the last occurrence
+	StartTime   string       `json:"start_time" gorm:"not null"`         // "HH:MM"
+	EndTime     string       `json:"end_time" gorm:"not null"`           // "HH:MM"
+	TotalAmount money.Amount `json:"total_amount" gorm:"not null"`
+	Status      string       `json:"status" gorm:"default:pending"` // pending, confirmed, cancelled
+	CreatedAt   time.Time    `json:"created_at"`
+
+	// Relationships
+	User         User          `json:"-" gorm:"foreignKey:UserID"`
+	Reservations []Reservation `json:"-" gorm:"foreignKey:SeriesID"`
+}
+
+// CreateSeriesRequest describes a recurring booking. The series ends at end_date or
+// after the given number of occurrences, whichever is given.
+type CreateSeriesRequest struct {
+	CourtIDs    []uint `json:"court_ids" binding:"required,min=1,max=4,dive,required"`
+	StartDate   string `json:"start_date" binding:"required"` // First occurrence; sets the weekday
+	StartTime   string `json:"start_time" binding:"required"`
+	EndTime     string `json:"end_time" binding:"required"`
+	Frequency   string `json:"frequency" binding:"required,oneof=weekly biweekly"`
+	EndDate     string `json:"end_date"`
+	Occurrences int    `json:"occurrences" binding:"omitempty,min=1,max=52"`
+}
+
+// ModifySeriesRequest moves every upcoming occurrence to a new start time on the same day.
+type ModifySeriesRequest struct {
+	StartTime string `json:"start_time" binding:"required"`
+}
+
+// SeriesConflict is an occurrence that cannot be booked, and why.
+type SeriesConflict struct {
+	Date     string `json:"date"`
+	CourtID  uint   `json:"court_id"`
+	TimeSlot string `json:"time_slot"`
+	Reason   string `json:"reason"`
+}
+
+type ReservationSeriesResponse struct {
+	ID          uint                  `json:"id"`
+	UserID      uint                  `json:"user_id"`
+	CourtIDs    []uint                `json:"court_ids"`
+	Frequency   string                `json:"frequency"`
+	StartDate   string                `json:"start_date"`
+	EndDate     string                `json:"end_date"`
+	StartTime   string                `json:"start_time"`
+	EndTime     string                `json:"end_time"`
+	TotalAmount money.Amount          `json:"total_amount"`
+	Status      string                `json:"status"`
+	CreatedAt   time.Time             `json:"created_at"`
+	Occurrences []ReservationResponse `json:"occurrences"`
+}
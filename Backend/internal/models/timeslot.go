@@ -1,8 +1,11 @@
 package models
 
+import "backend/pkg/money"
+
 type TimeSlot struct {
-	Time     string `json:"time"`
-	IsBooked bool   `json:"is_booked"`
+	Time     string       `json:"time"`
+	IsBooked bool         `json:"is_booked"`
+	Price    money.Amount `json:"price"`
 }
 
 type AvailableSlotResponse struct {
@@ -10,6 +10,8 @@ type User struct {
 	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
 	Phone     string    `json:"phone"`
 	Password  string    `json:"-" gorm:"not null"`
+	Role      string    `json:"role" gorm:"not null;default:customer"` // customer, staff, admin
+	VenueID   *uint     `json:"venue_id,omitempty" gorm:"index"`       // Venue a staff account works at
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -31,5 +33,7 @@ type UserResponse struct {
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
 	Phone     string    `json:"phone"`
+	Role      string    `json:"role"`
+	VenueID   *uint     `json:"venue_id,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
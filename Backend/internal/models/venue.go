@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+)
+
+// Venue is a hall operated by the club; it owns courts and staff accounts.
+type Venue struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Address   string    `json:"address" gorm:"not null"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Timezone  string    `json:"timezone" gorm:"not null;default:Asia/Jakarta"` // IANA zone name
+	Phone     string    `json:"phone"`
+	Email     string    `json:"email"`
+	Status    string    `json:"status" gorm:"default:active"` // active, inactive
+	CreatedAt time.Time `json:"created_at"`
+
+	// Opening hours used by courts that have no weekly hours of their own
+	OpenTime  string `json:"open_time" gorm:"not null;default:07:00"`  // "HH:MM"
+	CloseTime string `json:"close_time" gorm:"not null;default:21:00"` // "HH:MM", "24:00" for midnight
+}
+
+type CreateVenueRequest struct {
+	Name      string  `json:"name" binding:"required,max=100"`
+	Address   string  `json:"address" binding:"required,max=255"`
+	Latitude  float64 `json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"min=-180,max=180"`
+	Timezone  string  `json:"timezone"` // Defaults to Asia/Jakarta
+	Phone     string  `json:"phone" binding:"max=30"`
+	Email     string  `json:"email" binding:"omitempty,email"`
+	OpenTime  string  `json:"open_time"`  // Defaults to 07:00
+	CloseTime string  `json:"close_time"` // Defaults to 21:00
+}
+
+// UpdateVenueRequest changes only the fields that are present.
+type UpdateVenueRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Address   *string  `json:"address" binding:"omitempty,min=1,max=255"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+	Timezone  *string  `json:"timezone"`
+	Phone     *string  `json:"phone" binding:"omitempty,max=30"`
+	Email     *string  `json:"email" binding:"omitempty,email"`
+	Status    *string  `json:"status" binding:"omitempty,oneof=active inactive"`
+	OpenTime  *string  `json:"open_time"`
+	CloseTime *string  `json:"close_time"`
+}
+
+// SetUserRoleRequest changes a user's role; staff accounts are tied to one venue.
+type SetUserRoleRequest struct {
+	Role    string `json:"role" binding:"required,oneof=customer staff admin"`
+	VenueID *uint  `json:"venue_id"` // Required for staff
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// WaitlistEntry is a user waiting for a booked court, date and time range. When the range
+// frees up the first user waiting for it gets an offer: the slot is held for them as a
+// pending reservation until OfferExpiresAt.
+type WaitlistEntry struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	UserID          uint       `json:"user_id" gorm:"not null;index"`
+	CourtID         uint       `json:"court_id" gorm:"not null"`
+	ReservationDate time.Time  `json:"reservation_date" gorm:"type:date;not null"`
+	TimeSlot        string     `json:"time_slot" gorm:"not null"` // "HH:MM-HH:MM"
+	StartAt         time.Time  `json:"start_at" gorm:"not null"`
+	Status          string     `json:"status" gorm:"default:waiting;index"` // waiting, offered, claimed, expired, cancelled
+	ReservationID   *uint      `json:"reservation_id,omitempty"`            // Reservation holding the slot while offered
+	OfferExpiresAt  *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+
+	// Relationships
+	User  User  `json:"-" gorm:"foreignKey:UserID"`
+	Court Court `json:"-" gorm:"foreignKey:CourtID"`
+}
+
+// JoinWaitlistRequest names the court, date and time range to wait for, in the same form
+// as a booking.
+type JoinWaitlistRequest struct {
+	CreateReservationRequest
+}
+
+type WaitlistEntryResponse struct {
+	ID              uint       `json:"id"`
+	CourtID         uint       `json:"court_id"`
+	CourtName       string     `json:"court_name"`
+	ReservationDate string     `json:"reservation_date"`
+	TimeSlot        string     `json:"time_slot"`
+	Status          string     `json:"status"`
+	Position        int        `json:"position,omitempty"` // Place in the queue while waiting, 1 is next
+	ReservationID   *uint      `json:"reservation_id,omitempty"`
+	OfferExpiresAt  *time.Time `json:"offer_expires_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
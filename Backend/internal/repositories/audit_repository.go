@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// AuditEntry builds the audit log of a change. Repositories call it inside the change's
+// transaction, after the write, so the log is saved only with the change and its
+// snapshots include generated IDs.
+type AuditEntry func() (*models.AuditLog, error)
+
+// createAuditLog writes the entry within tx; a nil entry writes nothing.
+func createAuditLog(tx *gorm.DB, entry AuditEntry) error {
+	if entry == nil {
+		return nil
+	}
+	log, err := entry()
+	if err != nil {
+		return err
+	}
+	return tx.Create(log).Error
+}
+
+type AuditRepository interface {
+	GetAuditLogs(ctx context.Context, entityType string, entityID uint, limit int) ([]models.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// GetAuditLogs returns the newest entries first; empty entityType or zero entityID match all.
+func (r *auditRepository) GetAuditLogs(ctx context.Context, entityType string, entityID uint, limit int) ([]models.AuditLog, error) {
+	query := r.db.WithContext(ctx).Preload("Actor")
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID != 0 {
+		query = query.Where("entity_id = ?", entityID)
+	}
+
+	var logs []models.AuditLog
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&logs).Error
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ClosureRepository interface {
+	CreateClosure(ctx context.Context, closure *models.Closure, audit AuditEntry) (int64, error)
+	DeleteClosure(ctx context.Context, id uint, audit AuditEntry) error
+	GetClosureByID(ctx context.Context, id uint) (*models.Closure, error)
+	GetClosuresBetween(ctx context.Context, from, to time.Time) ([]models.Closure, error)
+	GetCourtClosuresBetween(ctx context.Context, courtID uint, from, to time.Time) ([]models.Closure, error)
+}
+
+type closureRepository struct {
+	db *gorm.DB
+}
+
+func NewClosureRepository(db *gorm.DB) ClosureRepository {
+	return &closureRepository{db: db}
+}
+
+// CreateClosure saves the closure and flags the active reservations it overlaps,
+// returning how many were flagged.
+func (r *closureRepository) CreateClosure(ctx context.Context, closure *models.Closure, audit AuditEntry) (int64, error) {
+	var flagged int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(closure).Error; err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.Reservation{}).
+			Where("status IN ? AND start_at < ? AND end_at > ?",
+				[]string{"pending", "confirmed"}, closure.EndAt, closure.StartAt)
+		switch {
+		case closure.CourtID != nil:
+			query = query.Where("court_id = ?", *closure.CourtID)
+		case closure.VenueID != nil:
+			query = query.Where("court_id IN (SELECT id FROM courts WHERE venue_id = ?)", *closure.VenueID)
+		}
+
+		result := query.Update("closure_id", closure.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+		flagged = result.RowsAffected
+		return createAuditLog(tx, audit)
+	})
+	return flagged, err
+}
+
+// DeleteClosure removes the closure and clears the flag on reservations it affected.
+func (r *closureRepository) DeleteClosure(ctx context.Context, id uint, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Reservation{}).
+			Where("closure_id = ?", id).
+			Update("closure_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Delete(&models.Closure{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *closureRepository) GetClosureByID(ctx context.Context, id uint) (*models.Closure, error) {
+	var closure models.Closure
+	err := r.db.WithContext(ctx).First(&closure, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &closure, nil
+}
+
+func (r *closureRepository) GetClosuresBetween(ctx context.Context, from, to time.Time) ([]models.Closure, error) {
+	var closures []models.Closure
+	err := r.db.WithContext(ctx).
+		Preload("Court").
+		Preload("Venue").
+		Where("start_at < ? AND end_at > ?", to, from).
+		Order("start_at ASC").
+		Find(&closures).Error
+	if err != nil {
+		return nil, err
+	}
+	return closures, nil
+}
+
+// GetCourtClosuresBetween returns the closures of the court, of its venue and of every
+// venue overlapping [from, to).
+func (r *closureRepository) GetCourtClosuresBetween(ctx context.Context, courtID uint, from, to time.Time) ([]models.Closure, error) {
+	var closures []models.Closure
+	err := r.db.WithContext(ctx).
+		Where(`(court_id = ? OR (court_id IS NULL AND
+			(venue_id IS NULL OR venue_id = (SELECT venue_id FROM courts WHERE id = ?))))`, courtID, courtID).
+		Where("start_at < ? AND end_at > ?", to, from).
+		Order("start_at ASC").
+		Find(&closures).Error
+	if err != nil {
+		return nil, err
+	}
+	return closures, nil
+}
@@ -2,16 +2,26 @@ package repositories
 
 import (
 	"backend/internal/models"
+	"backend/pkg/utils"
 	"context"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CourtRepository interface {
 	GetAllCourts(ctx context.Context) ([]models.Court, error)
+	GetVenueCourts(ctx context.Context, venueID uint) ([]models.Court, error)
+	ListCourts(ctx context.Context, venueID *uint) ([]models.Court, error)
+	CreateCourt(ctx context.Context, court *models.Court, hours []models.CourtOperatingHours, audit AuditEntry) error
+	UpdateCourt(ctx context.Context, court *models.Court, audit AuditEntry) error
+	GetWeeklyHours(ctx context.Context, courtID uint) ([]models.CourtOperatingHours, error)
+	ReplaceWeeklyHours(ctx context.Context, courtID uint, hours []models.CourtOperatingHours, audit AuditEntry) error
 	GetCourtByID(ctx context.Context, id uint) (*models.Court, error)
-	GetAvailableTimeSlots(ctx context.Context, date time.Time, courtID uint) ([]string, error)
+	GetAvailableTimeSlots(ctx context.Context, date time.Time, courtID uint, slots []string) ([]string, error)
+	GetOperatingHours(ctx context.Context, courtID uint, weekday time.Weekday) (*models.CourtOperatingHours, error)
+	GetSpecialHours(ctx context.Context, courtID uint, date time.Time) (*models.CourtSpecialHours, error)
 	CheckCourtAvailability(ctx context.Context, date time.Time, timeSlot string, courtID uint) (bool, error)
 	GetReservedSlots(ctx context.Context, date time.Time) ([]models.Reservation, error)
 }
@@ -26,53 +36,134 @@ func NewCourtRepository(db *gorm.DB) CourtRepository {
 
 func (r *courtRepository) GetAllCourts(ctx context.Context) ([]models.Court, error) {
 	var courts []models.Court
-	err := r.db.WithContext(ctx).Where("status = ?", "active").Find(&courts).Error
+	err := r.db.WithContext(ctx).Preload("Venue").Where("status = ?", "active").Find(&courts).Error
 	if err != nil {
 		return nil, err
 	}
 	return courts, nil
 }
 
-func (r *courtRepository) GetCourtByID(ctx context.Context, id uint) (*models.Court, error) {
-	var court models.Court
-	err := r.db.WithContext(ctx).First(&court, id).Error
+// GetVenueCourts returns the venue's active courts.
+func (r *courtRepository) GetVenueCourts(ctx context.Context, venueID uint) ([]models.Court, error) {
+	var courts []models.Court
+	err := r.db.WithContext(ctx).
+		Preload("Venue").
+		Where("venue_id = ? AND status = ?", venueID, "active").
+		Order("id ASC").
+		Find(&courts).Error
 	if err != nil {
 		return nil, err
 	}
-	return &court, nil
+	return courts, nil
 }
 
-func (r *courtRepository) GetAvailableTimeSlots(ctx context.Context, date time.Time, courtID uint) ([]string, error) {
-	// Define all possible time slots
-	allTimeSlots := []string{
-		"07:00-08:00", "08:00-09:00", "09:00-10:00", "10:00-11:00",
-		"11:00-12:00", "12:00-13:00", "13:00-14:00", "14:00-15:00",
-		"15:00-16:00", "16:00-17:00", "17:00-18:00", "18:00-19:00",
-		"19:00-20:00", "20:00-21:00",
+// ListCourts returns every court regardless of status, for administration, limited to
+// the venue when venueID is set.
+func (r *courtRepository) ListCourts(ctx context.Context, venueID *uint) ([]models.Court, error) {
+	var courts []models.Court
+	query := r.db.WithContext(ctx).Preload("Venue")
+	if venueID != nil {
+		query = query.Where("venue_id = ?", *venueID)
 	}
+	err := query.Order("id ASC").Find(&courts).Error
+	if err != nil {
+		return nil, err
+	}
+	return courts, nil
+}
+
+// CreateCourt inserts the court together with its weekly hours and the audit entry.
+func (r *courtRepository) CreateCourt(ctx context.Context, court *models.Court, hours []models.CourtOperatingHours, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(court).Error; err != nil {
+			return err
+		}
 
-	// Get reserved slots for this court and date
-	var reservedSlots []string
+		// Create replaces zero values with column defaults; write the policy as given
+		err := tx.Model(court).
+			Select("slot_minutes", "full_refund_hours", "partial_refund_hours", "partial_refund_percent").
+			Updates(court).Error
+		if err != nil {
+			return err
+		}
+
+		if len(hours) > 0 {
+			for i := range hours {
+				hours[i].CourtID = court.ID
+			}
+			if err := tx.Create(&hours).Error; err != nil {
+				return err
+			}
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *courtRepository) UpdateCourt(ctx context.Context, court *models.Court, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(court).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *courtRepository) GetWeeklyHours(ctx context.Context, courtID uint) ([]models.CourtOperatingHours, error) {
+	var hours []models.CourtOperatingHours
 	err := r.db.WithContext(ctx).
-		Model(&models.Reservation{}).
-		Where("court_id = ? AND reservation_date = ? AND status IN (?, ?)",
-			courtID, date, "pending", "confirmed").
-		Pluck("time_slot", &reservedSlots).Error
+		Where("court_id = ?", courtID).
+		Order("weekday ASC").
+		Find(&hours).Error
+	if err != nil {
+		return nil, err
+	}
+	return hours, nil
+}
 
+// ReplaceWeeklyHours swaps the court's whole weekly schedule; weekdays left out use the default hours.
+func (r *courtRepository) ReplaceWeeklyHours(ctx context.Context, courtID uint, hours []models.CourtOperatingHours, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("court_id = ?", courtID).Delete(&models.CourtOperatingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) > 0 {
+			for i := range hours {
+				hours[i].CourtID = courtID
+			}
+			if err := tx.Create(&hours).Error; err != nil {
+				return err
+			}
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *courtRepository) GetCourtByID(ctx context.Context, id uint) (*models.Court, error) {
+	var court models.Court
+	err := r.db.WithContext(ctx).Preload("Venue").First(&court, id).Error
 	if err != nil {
 		return nil, err
 	}
+	return &court, nil
+}
 
-	// Create a map of reserved slots for quick lookup
-	reservedMap := make(map[string]bool)
-	for _, slot := range reservedSlots {
-		reservedMap[slot] = true
+// GetAvailableTimeSlots returns the slots (generated from the court's schedule) that no
+// active reservation overlaps.
+func (r *courtRepository) GetAvailableTimeSlots(ctx context.Context, date time.Time, courtID uint, slots []string) ([]string, error) {
+	// Get reservations holding this court on the date
+	reservations, err := activeReservationsOn(ctx, r.db, date, courtID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Filter available slots
+	// Filter out slots overlapped by any reservation (multi-hour bookings block several slots)
 	var availableSlots []string
-	for _, slot := range allTimeSlots {
-		if !reservedMap[slot] {
+	for _, slot := range slots {
+		start, end, err := utils.ParseTimeSlot(slot)
+		if err != nil {
+			return nil, err
+		}
+		if !overlapsAny(reservations, start, end) {
 			availableSlots = append(availableSlots, slot)
 		}
 	}
@@ -81,24 +172,46 @@ func (r *courtRepository) GetAvailableTimeSlots(ctx context.Context, date time.T
 }
 
 func (r *courtRepository) CheckCourtAvailability(ctx context.Context, date time.Time, timeSlot string, courtID uint) (bool, error) {
-	var count int64
-	err := r.db.WithContext(ctx).
-		Model(&models.Reservation{}).
-		Where("court_id = ? AND reservation_date = ? AND time_slot = ? AND status IN (?, ?)",
-			courtID, date, timeSlot, "pending", "confirmed").
-		Count(&count).Error
+	start, end, err := utils.ParseTimeSlot(timeSlot)
+	if err != nil {
+		return false, err
+	}
 
+	reservations, err := activeReservationsOn(ctx, r.db, date, courtID)
 	if err != nil {
 		return false, err
 	}
 
-	return count == 0, nil
+	return !overlapsAny(reservations, start, end), nil
+}
+
+func (r *courtRepository) GetOperatingHours(ctx context.Context, courtID uint, weekday time.Weekday) (*models.CourtOperatingHours, error) {
+	var hours models.CourtOperatingHours
+	err := r.db.WithContext(ctx).
+		Where("court_id = ? AND weekday = ?", courtID, int(weekday)).
+		First(&hours).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hours, nil
+}
+
+func (r *courtRepository) GetSpecialHours(ctx context.Context, courtID uint, date time.Time) (*models.CourtSpecialHours, error) {
+	var hours models.CourtSpecialHours
+	err := r.db.WithContext(ctx).
+		Where("court_id = ? AND date = ?", courtID, date).
+		First(&hours).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hours, nil
 }
 
 func (r *courtRepository) GetReservedSlots(ctx context.Context, date time.Time) ([]models.Reservation, error) {
 	var reservations []models.Reservation
 	err := r.db.WithContext(ctx).
-		Where("reservation_date = ? AND status IN (?, ?)", date, "pending", "confirmed").
+		Scopes(holdingCourt(time.Now())).
+		Where("reservation_date = ?", date).
 		Find(&reservations).Error
 
 	if err != nil {
@@ -0,0 +1,181 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"backend/pkg/money"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSplitClosed is returned when a split reservation no longer accepts the change: it was
+// confirmed, cancelled or expired, or the share was already settled.
+var ErrSplitClosed = errors.New("split payment is closed")
+
+// ParticipantRepository stores the players sharing a split reservation and settles their shares.
+type ParticipantRepository interface {
+	CreateSplit(ctx context.Context, reservationID uint, deadline, holdExpiresAt time.Time, participants []models.ReservationParticipant) error
+	GetParticipants(ctx context.Context, reservationID uint) ([]models.ReservationParticipant, error)
+	GetParticipant(ctx context.Context, reservationID uint, userID uint) (*models.ReservationParticipant, error)
+	GetUserParticipations(ctx context.Context, userID uint) ([]models.ReservationParticipant, error)
+	MarkSharePaid(ctx context.Context, participantID uint, at time.Time) (bool, error)
+	CoverRemainder(ctx context.Context, reservationID uint, at time.Time) (money.Amount, error)
+}
+
+type participantRepository struct {
+	db *gorm.DB
+}
+
+func NewParticipantRepository(db *gorm.DB) ParticipantRepository {
+	return &participantRepository{db: db}
+}
+
+// CreateSplit adds the participants and moves the reservation's hold to cover the split
+// deadline. Only a pending reservation that is not split yet can be split.
+func (r *participantRepository) CreateSplit(ctx context.Context, reservationID uint, deadline, holdExpiresAt time.Time, participants []models.ReservationParticipant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ? AND split_deadline IS NULL", reservationID, "pending").
+			Updates(map[string]interface{}{
+				"split_deadline":  deadline,
+				"hold_expires_at": holdExpiresAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSplitClosed
+		}
+
+		for i := range participants {
+			participants[i].ReservationID = reservationID
+		}
+		return tx.Create(&participants).Error
+	})
+}
+
+func (r *participantRepository) GetParticipants(ctx context.Context, reservationID uint) ([]models.ReservationParticipant, error) {
+	var participants []models.ReservationParticipant
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("reservation_id = ?", reservationID).
+		Order("id ASC").
+		Find(&participants).Error
+	return participants, err
+}
+
+func (r *participantRepository) GetParticipant(ctx context.Context, reservationID uint, userID uint) (*models.ReservationParticipant, error) {
+	var participant models.ReservationParticipant
+	err := r.db.WithContext(ctx).
+		Where("reservation_id = ? AND user_id = ?", reservationID, userID).
+		First(&participant).Error
+	if err != nil {
+		return nil, err
+	}
+	return &participant, nil
+}
+
+// GetUserParticipations lists the split reservations the user plays in, latest first.
+func (r *participantRepository) GetUserParticipations(ctx context.Context, userID uint) ([]models.ReservationParticipant, error) {
+	var participants []models.ReservationParticipant
+	err := r.db.WithContext(ctx).
+		Preload("Reservation.Court").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&participants).Error
+	return participants, err
+}
+
+// MarkSharePaid records a paid share and confirms the reservation once no share is left
+// unpaid. It reports whether the reservation was confirmed, and ErrSplitClosed when the
+// reservation is no longer pending or the share was already settled.
+func (r *participantRepository) MarkSharePaid(ctx context.Context, participantID uint, at time.Time) (bool, error) {
+	confirmed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var participant models.ReservationParticipant
+		if err := tx.First(&participant, participantID).Error; err != nil {
+			return err
+		}
+		if err := lockPendingSplit(tx, participant.ReservationID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.ReservationParticipant{}).
+			Where("id = ? AND status = ?", participantID, "pending").
+			Updates(map[string]interface{}{"status": "paid", "paid_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSplitClosed
+		}
+
+		var unpaid int64
+		err := tx.Model(&models.ReservationParticipant{}).
+			Where("reservation_id = ? AND status = ?", participant.ReservationID, "pending").
+			Count(&unpaid).Error
+		if err != nil || unpaid > 0 {
+			return err
+		}
+
+		confirmed = true
+		return tx.Model(&models.Reservation{}).
+			Where("id = ?", participant.ReservationID).
+			Update("status", "confirmed").Error
+	})
+	return confirmed, err
+}
+
+// CoverRemainder marks every unpaid share as covered by the organiser and confirms the
+// reservation. It returns the amount of the shares covered.
+func (r *participantRepository) CoverRemainder(ctx context.Context, reservationID uint, at time.Time) (money.Amount, error) {
+	var covered money.Amount
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockPendingSplit(tx, reservationID); err != nil {
+			return err
+		}
+
+		var unpaid []models.ReservationParticipant
+		err := tx.Where("reservation_id = ? AND status = ?", reservationID, "pending").Find(&unpaid).Error
+		if err != nil {
+			return err
+		}
+		if len(unpaid) == 0 {
+			return ErrSplitClosed
+		}
+		for _, participant := range unpaid {
+			covered += participant.ShareAmount
+		}
+
+		err = tx.Model(&models.ReservationParticipant{}).
+			Where("reservation_id = ? AND status = ?", reservationID, "pending").
+			Updates(map[string]interface{}{"status": "covered", "paid_at": at}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Reservation{}).
+			Where("id = ?", reservationID).
+			Update("status", "confirmed").Error
+	})
+	return covered, err
+}
+
+// lockPendingSplit locks a split reservation for the rest of the transaction, so shares
+// settling at the same time are applied one after another. It returns ErrSplitClosed when
+// the reservation is no longer pending.
+func lockPendingSplit(tx *gorm.DB, reservationID uint) error {
+	var reservation models.Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "status").
+		First(&reservation, reservationID).Error
+	if err != nil {
+		return err
+	}
+	if reservation.Status != "pending" {
+		return ErrSplitClosed
+	}
+	return nil
+}
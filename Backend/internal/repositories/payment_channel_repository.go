@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PaymentChannelRepository interface {
+	GetEnabledChannels(ctx context.Context) ([]models.PaymentChannel, error)
+	GetEnabledChannel(ctx context.Context, paymentMethod string, bank string) (*models.PaymentChannel, error)
+}
+
+type paymentChannelRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentChannelRepository(db *gorm.DB) PaymentChannelRepository {
+	return &paymentChannelRepository{db: db}
+}
+
+func (r *paymentChannelRepository) GetEnabledChannels(ctx context.Context) ([]models.PaymentChannel, error) {
+	var channels []models.PaymentChannel
+	err := r.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Order("sort_order ASC, id ASC").
+		Find(&channels).Error
+	return channels, err
+}
+
+// GetEnabledChannel finds the enabled channel for a payment method; bank is only
+// matched for bank_transfer.
+func (r *paymentChannelRepository) GetEnabledChannel(ctx context.Context, paymentMethod string, bank string) (*models.PaymentChannel, error) {
+	var channel models.PaymentChannel
+	err := r.db.WithContext(ctx).
+		Where("enabled = ? AND payment_method = ? AND bank = ?", true, paymentMethod, bank).
+		First(&channel).Error
+	if err != nil {
+		return nil, err
+	}
+	return &channel, nil
+}
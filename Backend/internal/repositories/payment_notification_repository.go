@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type PaymentNotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *models.PaymentNotification) error
+}
+
+type paymentNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentNotificationRepository(db *gorm.DB) PaymentNotificationRepository {
+	return &paymentNotificationRepository{db: db}
+}
+
+func (r *paymentNotificationRepository) CreateNotification(ctx context.Context, notification *models.PaymentNotification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
@@ -3,18 +3,40 @@ package repositories
 import (
 	"backend/internal/models"
 	"context"
+	"errors"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrActivePaymentExists is returned when the reservation already has a pending payment
+// for the same purpose, or the series one, usually because a concurrent request created it
+// first.
+var ErrActivePaymentExists = errors.New("reservation already has a pending payment")
+
 type PaymentRepository interface {
 	CreatePayment(ctx context.Context, payment *models.Payment) error
 	UpdatePaymentStatus(ctx context.Context, orderID string, status string) error
+	TransitionPaymentStatus(ctx context.Context, payment *models.Payment, fromStatus string) (bool, error)
+	SettleReservationPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, bool, error)
+	SettleTopUpPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, bool, error)
+	SettleSeriesPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, []models.Reservation, error)
+	SettleUnneededPayment(ctx context.Context, payment *models.Payment, fromStatus string, refund *models.Refund) (bool, error)
+	CreateSettledReservationPayment(ctx context.Context, payment *models.Payment, now time.Time, audit AuditEntry) (bool, error)
 	GetPaymentByOrderID(ctx context.Context, orderID string) (*models.Payment, error)
 
 	GetUserPayments(ctx context.Context, userID uint) ([]models.Payment, error)
 	GetPaymentByID(ctx context.Context, paymentID uint, userID uint) (*models.Payment, error)
 	Update(ctx context.Context, payment *models.Payment) error
+	GetPendingPaymentsCreatedBefore(ctx context.Context, before time.Time) ([]models.Payment, error)
+	GetPaidPaymentsByReservationID(ctx context.Context, reservationID uint) ([]models.Payment, error)
+	GetActivePaymentByReservationID(ctx context.Context, reservationID uint) (*models.Payment, error)
+	GetPaidPaymentBySeriesID(ctx context.Context, seriesID uint) (*models.Payment, error)
+	GetActivePaymentBySeriesID(ctx context.Context, seriesID uint) (*models.Payment, error)
+	GetActivePaymentByParticipantID(ctx context.Context, participantID uint) (*models.Payment, error)
+	GetActivePaymentByPurpose(ctx context.Context, reservationID uint, purpose string) (*models.Payment, error)
+	GetPaidSplitPaymentsOfExpiredReservations(ctx context.Context) ([]models.Payment, error)
 }
 
 type paymentRepository struct {
@@ -29,7 +51,11 @@ func NewPaymentRepository(db *gorm.DB) PaymentRepository {
 // CREATE PAYMENT
 // -----------------------------------------------------
 func (r *paymentRepository) CreatePayment(ctx context.Context, payment *models.Payment) error {
-	return r.db.WithContext(ctx).Create(payment).Error
+	err := r.db.WithContext(ctx).Create(payment).Error
+	if isUniqueViolation(err) {
+		return ErrActivePaymentExists
+	}
+	return err
 }
 
 // -----------------------------------------------------
@@ -42,6 +68,202 @@ func (r *paymentRepository) UpdatePaymentStatus(ctx context.Context, orderID str
 		Update("status", status).Error
 }
 
+// -----------------------------------------------------
+// TRANSITION PAYMENT STATUS (COMPARE-AND-SET ON STATUS)
+// -----------------------------------------------------
+func (r *paymentRepository) TransitionPaymentStatus(ctx context.Context, payment *models.Payment, fromStatus string) (bool, error) {
+	return transitionPayment(r.db.WithContext(ctx), payment, fromStatus)
+}
+
+// -----------------------------------------------------
+// SETTLE BOOKING PAYMENT (PAYMENT AND RESERVATION IN ONE TRANSACTION)
+// -----------------------------------------------------
+// SettleReservationPayment marks a booking payment as paid and confirms its reservation
+// together, so a failed confirmation leaves the payment pending for the next retry. The
+// reservation is only confirmed while it is still pending; the second result reports
+// false when it was cancelled or expired before the money arrived, and lateRefund is then
+// saved in the same transaction so the money is owed back even if refunding fails.
+func (r *paymentRepository) SettleReservationPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, bool, error) {
+	applied, confirmed := false, false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = transitionPayment(tx, payment, fromStatus)
+		if err != nil || !applied {
+			return err
+		}
+
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", payment.ReservationID, "pending").
+			Update("status", "confirmed")
+		if result.Error != nil {
+			return result.Error
+		}
+		confirmed = result.RowsAffected > 0
+		if confirmed {
+			return nil
+		}
+		return tx.Create(lateRefund).Error
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return applied, confirmed, nil
+}
+
+// -----------------------------------------------------
+// SETTLE TOP-UP PAYMENT (PAYMENT AND AMOUNT DUE IN ONE TRANSACTION)
+// -----------------------------------------------------
+// SettleTopUpPayment marks a reschedule top-up as paid and clears the amount its
+// reservation owes, together. The amount is only cleared while the booking is still
+// confirmed or checked in and owes something; the second result reports false otherwise,
+// and lateRefund is then saved in the same transaction.
+func (r *paymentRepository) SettleTopUpPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, bool, error) {
+	applied, cleared := false, false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = transitionPayment(tx, payment, fromStatus)
+		if err != nil || !applied {
+			return err
+		}
+
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status IN ? AND amount_due > 0", payment.ReservationID, []string{"confirmed", "checked_in"}).
+			Update("amount_due", 0)
+		if result.Error != nil {
+			return result.Error
+		}
+		cleared = result.RowsAffected > 0
+		if cleared {
+			return nil
+		}
+		return tx.Create(lateRefund).Error
+	})
+	if err != nil {
+		return false, false, err
+	}
+	return applied, cleared, nil
+}
+
+// -----------------------------------------------------
+// SETTLE SERIES PAYMENT (PAYMENT AND OCCURRENCES IN ONE TRANSACTION)
+// -----------------------------------------------------
+// SettleSeriesPayment marks a series payment as paid and confirms the occurrences that are
+// still pending, and the series with them, together. It returns the occurrences it
+// confirmed. Those cancelled or expired before the money arrived stay as they are:
+// lateRefund holds the whole booking amount, is reduced by the price of the confirmed
+// occurrences and, if anything is left, saved in the same transaction.
+func (r *paymentRepository) SettleSeriesPayment(ctx context.Context, payment *models.Payment, fromStatus string, lateRefund *models.Refund) (bool, []models.Reservation, error) {
+	applied := false
+	var confirmed []models.Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = transitionPayment(tx, payment, fromStatus)
+		if err != nil || !applied {
+			return err
+		}
+
+		err = tx.Model(&confirmed).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "total_amount"}}}).
+			Where("series_id = ? AND status = ?", *payment.SeriesID, "pending").
+			Update("status", "confirmed").Error
+		if err != nil {
+			return err
+		}
+		if len(confirmed) > 0 {
+			err := tx.Model(&models.ReservationSeries{}).
+				Where("id = ? AND status = ?", *payment.SeriesID, "pending").
+				Update("status", "confirmed").Error
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, occurrence := range confirmed {
+			lateRefund.Amount -= occurrence.TotalAmount
+		}
+		if lateRefund.Amount <= 0 {
+			return nil
+		}
+		return tx.Create(lateRefund).Error
+	})
+	if err != nil {
+		return false, nil, err
+	}
+	return applied, confirmed, nil
+}
+
+// -----------------------------------------------------
+// SETTLE A PAYMENT THAT IS NO LONGER NEEDED (PAYMENT AND REFUND IN ONE TRANSACTION)
+// -----------------------------------------------------
+// SettleUnneededPayment marks a payment as paid and saves the refund of its money together,
+// for money that arrived after its order was closed. It reports false when the payment was
+// changed by another request, and nothing is saved then.
+func (r *paymentRepository) SettleUnneededPayment(ctx context.Context, payment *models.Payment, fromStatus string, refund *models.Refund) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		applied, err = transitionPayment(tx, payment, fromStatus)
+		if err != nil || !applied {
+			return err
+		}
+		return tx.Create(refund).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// -----------------------------------------------------
+// SAVE A PAYMENT TAKEN OUTSIDE THE GATEWAY (CASH AT THE FRONT DESK)
+// -----------------------------------------------------
+// CreateSettledReservationPayment confirms the payment's reservation and saves the already
+// paid payment in one transaction, along with its audit log. The reservation is only
+// confirmed while it is pending and its hold has not lapsed at now; otherwise nothing is
+// saved and false is returned.
+func (r *paymentRepository) CreateSettledReservationPayment(ctx context.Context, payment *models.Payment, now time.Time, audit AuditEntry) (bool, error) {
+	confirmed := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ? AND (hold_expires_at IS NULL OR hold_expires_at > ?)", payment.ReservationID, "pending", now).
+			Update("status", "confirmed")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		confirmed = true
+		return createAuditLog(tx, audit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// transitionPayment saves the payment's new status only if it is still fromStatus.
+func transitionPayment(tx *gorm.DB, payment *models.Payment, fromStatus string) (bool, error) {
+	result := tx.Model(&models.Payment{}).
+		Where("id = ? AND status = ?", payment.ID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":             payment.Status,
+			"transaction_id":     payment.TransactionID,
+			"transaction_status": payment.TransactionStatus,
+			"payment_time":       payment.PaymentTime,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
 // -----------------------------------------------------
 // GET PAYMENT BY MIDTRANS ORDER ID
 // -----------------------------------------------------
@@ -66,14 +288,24 @@ func (r *paymentRepository) GetUserPayments(ctx context.Context, userID uint) ([
 	var payments []models.Payment
 
 	err := r.db.WithContext(ctx).
-		Joins("JOIN reservations ON reservations.id = payments.reservation_id").
-		Where("reservations.user_id = ?", userID).
+		Scopes(paidBy(userID)).
 		Preload("Reservation").
 		Find(&payments).Error
 
 	return payments, err
 }
 
+// paidBy limits a query to the payments the user made: a player's share of a split
+// reservation, and otherwise every payment of the user's own reservations.
+func paidBy(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN reservations ON reservations.id = payments.reservation_id").
+			Joins("LEFT JOIN reservation_participants ON reservation_participants.id = payments.participant_id").
+			Where("(payments.participant_id IS NULL AND reservations.user_id = ?) OR reservation_participants.user_id = ?", userID, userID)
+	}
+}
+
 // -----------------------------------------------------
 // GET PAYMENT BY ID (AND BELONGS TO USER)
 // -----------------------------------------------------
@@ -81,9 +313,10 @@ func (r *paymentRepository) GetPaymentByID(ctx context.Context, paymentID uint,
 	var payment models.Payment
 
 	err := r.db.WithContext(ctx).
-		Joins("JOIN reservations ON reservations.id = payments.reservation_id").
-		Where("payments.id = ? AND reservations.user_id = ?", paymentID, userID).
+		Scopes(paidBy(userID)).
+		Where("payments.id = ?", paymentID).
 		Preload("Reservation").
+		Preload("Refunds").
 		First(&payment).Error
 
 	if err != nil {
@@ -99,3 +332,141 @@ func (r *paymentRepository) GetPaymentByID(ctx context.Context, paymentID uint,
 func (r *paymentRepository) Update(ctx context.Context, payment *models.Payment) error {
 	return r.db.WithContext(ctx).Save(payment).Error
 }
+
+// -----------------------------------------------------
+// GET STALE PENDING PAYMENTS (FOR RECONCILIATION)
+// -----------------------------------------------------
+func (r *paymentRepository) GetPendingPaymentsCreatedBefore(ctx context.Context, before time.Time) ([]models.Payment, error) {
+	var payments []models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ?", "pending", before).
+		Order("created_at ASC").
+		Find(&payments).Error
+
+	return payments, err
+}
+
+// -----------------------------------------------------
+// GET SETTLED PAYMENTS FOR A RESERVATION (NEWEST FIRST)
+// -----------------------------------------------------
+// A rescheduled reservation can have a top-up next to its booking payment, and either
+// may already be partially refunded.
+func (r *paymentRepository) GetPaidPaymentsByReservationID(ctx context.Context, reservationID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("reservation_id = ? AND series_id IS NULL AND status IN (?, ?)", reservationID, "paid", "partially_refunded").
+		Preload("Refunds").
+		Order("created_at DESC").
+		Find(&payments).Error
+
+	return payments, err
+}
+
+// -----------------------------------------------------
+// GET PENDING OR PAID PAYMENT FOR A RESERVATION
+// -----------------------------------------------------
+func (r *paymentRepository) GetActivePaymentByReservationID(ctx context.Context, reservationID uint) (*models.Payment, error) {
+	var payment models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("reservation_id = ? AND status IN (?, ?)", reservationID, "pending", "paid").
+		Order("created_at DESC").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// -----------------------------------------------------
+// GET SETTLED PAYMENT FOR A SERIES
+// -----------------------------------------------------
+// A series payment stays partially_refunded while some occurrences are still booked.
+func (r *paymentRepository) GetPaidPaymentBySeriesID(ctx context.Context, seriesID uint) (*models.Payment, error) {
+	var payment models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND status IN (?, ?)", seriesID, "paid", "partially_refunded").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// -----------------------------------------------------
+// GET PENDING OR PAID PAYMENT FOR A SERIES
+// -----------------------------------------------------
+func (r *paymentRepository) GetActivePaymentBySeriesID(ctx context.Context, seriesID uint) (*models.Payment, error) {
+	var payment models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND status IN (?, ?)", seriesID, "pending", "paid").
+		Order("created_at DESC").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// -----------------------------------------------------
+// GET PENDING OR PAID PAYMENT FOR A PLAYER'S SHARE
+// -----------------------------------------------------
+func (r *paymentRepository) GetActivePaymentByParticipantID(ctx context.Context, participantID uint) (*models.Payment, error) {
+	var payment models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("participant_id = ? AND status IN (?, ?)", participantID, "pending", "paid").
+		Order("created_at DESC").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// -----------------------------------------------------
+// GET PENDING OR PAID PAYMENT OF ONE PURPOSE FOR A RESERVATION
+// -----------------------------------------------------
+func (r *paymentRepository) GetActivePaymentByPurpose(ctx context.Context, reservationID uint, purpose string) (*models.Payment, error) {
+	var payment models.Payment
+
+	err := r.db.WithContext(ctx).
+		Where("reservation_id = ? AND purpose = ? AND status IN (?, ?)", reservationID, purpose, "pending", "paid").
+		Order("created_at DESC").
+		First(&payment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+// -----------------------------------------------------
+// GET SHARES PAID FOR SPLIT RESERVATIONS THAT EXPIRED
+// -----------------------------------------------------
+// A split reservation whose hold lapsed before it was fully paid never goes ahead, so
+// whatever its players paid is due back.
+func (r *paymentRepository) GetPaidSplitPaymentsOfExpiredReservations(ctx context.Context) ([]models.Payment, error) {
+	var payments []models.Payment
+
+	err := r.db.WithContext(ctx).
+		Joins("JOIN reservations ON reservations.id = payments.reservation_id").
+		Where("payments.purpose IN (?, ?) AND payments.status = ? AND reservations.status = ?", "share", "remainder", "paid", "expired").
+		Preload("Refunds").
+		Find(&payments).Error
+
+	return payments, err
+}
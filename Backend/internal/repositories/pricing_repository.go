@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPricingRuleOverlap is returned when a rule would apply to the same time as another
+	// rule of the court with the same priority, so neither rate would clearly win.
+	ErrPricingRuleOverlap = errors.New("pricing rule overlaps another rule of the court with the same priority")
+	// ErrHolidayExists is returned when a holiday is already recorded on the date.
+	ErrHolidayExists = errors.New("a holiday is already recorded on this date")
+)
+
+type PricingRepository interface {
+	GetRulesForDate(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error)
+	GetCurrentRules(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error)
+	IsHoliday(ctx context.Context, date time.Time) (bool, error)
+
+	ListRules(ctx context.Context, courtID uint) ([]models.PricingRule, error)
+	GetRuleByID(ctx context.Context, id uint) (*models.PricingRule, error)
+	SaveRule(ctx context.Context, rule *models.PricingRule, audit AuditEntry) error
+	DeleteRule(ctx context.Context, id uint, audit AuditEntry) error
+
+	ListHolidays(ctx context.Context) ([]models.Holiday, error)
+	GetHolidayByID(ctx context.Context, id uint) (*models.Holiday, error)
+	SaveHoliday(ctx context.Context, holiday *models.Holiday, audit AuditEntry) error
+	DeleteHoliday(ctx context.Context, id uint, audit AuditEntry) error
+}
+
+type pricingRepository struct {
+	db *gorm.DB
+}
+
+func NewPricingRepository(db *gorm.DB) PricingRepository {
+	return &pricingRepository{db: db}
+}
+
+// GetRulesForDate returns the court's rules whose date range covers date, highest priority first.
+func (r *pricingRepository) GetRulesForDate(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	err := r.db.WithContext(ctx).
+		Where("court_id = ?", courtID).
+		Where("start_date IS NULL OR start_date <= ?", date).
+		Where("end_date IS NULL OR end_date >= ?", date).
+		Order("priority DESC, id DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// GetCurrentRules returns the court's rules that have not ended before date, including
+// date-range overrides that start later, highest priority first.
+func (r *pricingRepository) GetCurrentRules(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	err := r.db.WithContext(ctx).
+		Where("court_id = ?", courtID).
+		Where("end_date IS NULL OR end_date >= ?", date).
+		Order("priority DESC, id DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *pricingRepository) IsHoliday(ctx context.Context, date time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Holiday{}).
+		Where("date = ?", date).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListRules returns every rule of the court, highest priority first.
+func (r *pricingRepository) ListRules(ctx context.Context, courtID uint) ([]models.PricingRule, error) {
+	var rules []models.PricingRule
+	err := r.db.WithContext(ctx).
+		Where("court_id = ?", courtID).
+		Order("priority DESC, id DESC").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *pricingRepository) GetRuleByID(ctx context.Context, id uint) (*models.PricingRule, error) {
+	var rule models.PricingRule
+	if err := r.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// SaveRule creates or updates the rule under the court's pricing lock, rejecting it with
+// ErrPricingRuleOverlap when another rule of the same priority applies at the same time.
+func (r *pricingRepository) SaveRule(ctx context.Context, rule *models.PricingRule, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", pricingLockNamespace, rule.CourtID).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.PricingRule{}).
+			Scopes(overlappingRules(rule)).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrPricingRuleOverlap
+		}
+
+		if err := tx.Save(rule).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+// overlappingRules matches the court's other rules of the same priority whose day types,
+// date ranges and time bands all intersect the rule's. Times are stored as "HH:MM", so
+// they compare as strings.
+func overlappingRules(rule *models.PricingRule) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("court_id = ? AND priority = ? AND id <> ?", rule.CourtID, rule.Priority, rule.ID)
+
+		// Holidays are priced like weekends, so those two day types meet
+		switch rule.DayType {
+		case "weekday":
+			db = db.Where("COALESCE(day_type, '') IN ?", []string{"", "weekday"})
+		case "weekend", "holiday":
+			db = db.Where("COALESCE(day_type, '') IN ?", []string{"", "weekend", "holiday"})
+		}
+
+		if rule.EndDate != nil {
+			db = db.Where("start_date IS NULL OR start_date <= ?", *rule.EndDate)
+		}
+		if rule.StartDate != nil {
+			db = db.Where("end_date IS NULL OR end_date >= ?", *rule.StartDate)
+		}
+		if rule.StartTime != "" {
+			db = db.Where("COALESCE(start_time, '') = '' OR (start_time < ? AND end_time > ?)", rule.EndTime, rule.StartTime)
+		}
+		return db
+	}
+}
+
+// DeleteRule removes the rule. Price lines of past bookings keep its name and rate.
+func (r *pricingRepository) DeleteRule(ctx context.Context, id uint, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.PricingRule{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *pricingRepository) ListHolidays(ctx context.Context) ([]models.Holiday, error) {
+	var holidays []models.Holiday
+	if err := r.db.WithContext(ctx).Order("date ASC").Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+	return holidays, nil
+}
+
+func (r *pricingRepository) GetHolidayByID(ctx context.Context, id uint) (*models.Holiday, error) {
+	var holiday models.Holiday
+	if err := r.db.WithContext(ctx).First(&holiday, id).Error; err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+// SaveHoliday creates or updates the holiday; ErrHolidayExists when the date is taken.
+func (r *pricingRepository) SaveHoliday(ctx context.Context, holiday *models.Holiday, audit AuditEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(holiday).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+	if isUniqueViolation(err) {
+		return ErrHolidayExists
+	}
+	return err
+}
+
+func (r *pricingRepository) DeleteHoliday(ctx context.Context, id uint, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.Holiday{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+// pricingLockNamespace keys the advisory locks taken per court while saving its rules.
+const pricingLockNamespace = 1002
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSaveRuleRejectsOverlappingRules(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	court := models.Court{Name: "Pricing Court", Location: "-", PricePerHour: 50000}
+	if err := db.Create(&court).Error; err != nil {
+		t.Fatalf("creating court: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("court_id = ?", court.ID).Delete(&models.PricingRule{})
+		db.Delete(&court)
+	})
+
+	repo := NewPricingRepository(db)
+	peak := &models.PricingRule{
+		CourtID: court.ID, Name: "Weekend peak", DayType: "weekend",
+		StartTime: "17:00", EndTime: "21:00", PricePerHour: 80000,
+	}
+	if err := repo.SaveRule(ctx, peak, nil); err != nil {
+		t.Fatalf("saving first rule: %v", err)
+	}
+
+	june := time.Date(2026, time.June, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rule    models.PricingRule
+		wantErr error
+	}{
+		{"overlapping band", models.PricingRule{DayType: "weekend", StartTime: "20:00", EndTime: "22:00"}, ErrPricingRuleOverlap},
+		{"whole day", models.PricingRule{}, ErrPricingRuleOverlap},
+		{"holiday meets weekend", models.PricingRule{DayType: "holiday", StartTime: "18:00", EndTime: "19:00"}, ErrPricingRuleOverlap},
+		{"date range override", models.PricingRule{StartDate: &june, EndDate: &june}, ErrPricingRuleOverlap},
+		{"adjacent band", models.PricingRule{DayType: "weekend", StartTime: "21:00", EndTime: "24:00"}, nil},
+		{"weekday band", models.PricingRule{DayType: "weekday", StartTime: "17:00", EndTime: "21:00"}, nil},
+		{"higher priority", models.PricingRule{DayType: "weekend", StartTime: "17:00", EndTime: "21:00", Priority: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := tt.rule
+			rule.CourtID = court.ID
+			rule.Name = tt.name
+			rule.PricePerHour = 60000
+
+			err := repo.SaveRule(ctx, &rule, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SaveRule error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				db.Delete(&rule)
+			}
+		})
+	}
+
+	// Updating a rule does not count the rule itself as an overlap
+	peak.PricePerHour = 90000
+	if err := repo.SaveRule(ctx, peak, nil); err != nil {
+		t.Fatalf("updating rule: %v", err)
+	}
+}
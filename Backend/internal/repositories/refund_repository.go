@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"backend/pkg/money"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefundRepository interface {
+	CreateRefund(ctx context.Context, refund *models.Refund) error
+	UpdateRefund(ctx context.Context, refund *models.Refund) error
+	GetRefundedAmount(ctx context.Context, paymentID uint) (money.Amount, error)
+	GetSucceededRefundAmount(ctx context.Context, paymentID uint) (money.Amount, error)
+	GetPendingRefundsUpdatedBefore(ctx context.Context, before time.Time) ([]models.Refund, error)
+}
+
+type refundRepository struct {
+	db *gorm.DB
+}
+
+func NewRefundRepository(db *gorm.DB) RefundRepository {
+	return &refundRepository{db: db}
+}
+
+func (r *refundRepository) CreateRefund(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+func (r *refundRepository) UpdateRefund(ctx context.Context, refund *models.Refund) error {
+	return r.db.WithContext(ctx).Save(refund).Error
+}
+
+// GetRefundedAmount sums the refunds of a payment that succeeded or are still under way,
+// so two refunds started at the same time cannot both take the same money.
+func (r *refundRepository) GetRefundedAmount(ctx context.Context, paymentID uint) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.WithContext(ctx).
+		Model(&models.Refund{}).
+		Where("payment_id = ? AND status IN (?, ?)", paymentID, "pending", "succeeded").
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetSucceededRefundAmount sums the refunds of a payment the gateway has accepted.
+func (r *refundRepository) GetSucceededRefundAmount(ctx context.Context, paymentID uint) (money.Amount, error) {
+	var total money.Amount
+	err := r.db.WithContext(ctx).
+		Model(&models.Refund{}).
+		Where("payment_id = ? AND status = ?", paymentID, "succeeded").
+		Select("COALESCE(SUM(amount), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// GetPendingRefundsUpdatedBefore lists the refunds still waiting for the gateway whose last
+// attempt was before the given time, together with their payments.
+func (r *refundRepository) GetPendingRefundsUpdatedBefore(ctx context.Context, before time.Time) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND COALESCE(updated_at, created_at) < ?", "pending", before).
+		Preload("Payment").
+		Order("created_at ASC").
+		Find(&refunds).Error
+	return refunds, err
+}
@@ -2,19 +2,31 @@ package repositories
 
 import (
 	"backend/internal/models"
+	"backend/pkg/utils"
 	"context"
+	"errors"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
 type ReservationRepository interface {
-	CreateReservation(ctx context.Context, reservation *models.Reservation) error
+	CreateReservation(ctx context.Context, reservation *models.Reservation, audit AuditEntry) error
 	GetReservationByID(ctx context.Context, id uint) (*models.Reservation, error)
 	GetUserReservations(ctx context.Context, userID uint) ([]models.Reservation, error)
+	SearchReservations(ctx context.Context, filter ReservationFilter) ([]models.Reservation, int64, error)
+	MoveReservation(ctx context.Context, reservation *models.Reservation, audit AuditEntry) error
+	RescheduleReservation(ctx context.Context, reservation *models.Reservation, expected *models.Reservation) error
 	GetReservationsByDateAndCourt(ctx context.Context, date time.Time, courtID uint) ([]models.Reservation, error)
 	UpdateReservationStatus(ctx context.Context, id uint, status string) error
+	TransitionReservationStatus(ctx context.Context, id uint, fromStatus string, toStatus string, audit AuditEntry) (bool, error)
 	CheckExistingReservation(ctx context.Context, date time.Time, timeSlot string, courtID uint) (bool, error)
+	CheckExistingReservationExcept(ctx context.Context, date time.Time, timeSlot string, courtID uint, excludeID uint) (bool, error)
+	ExpireStaleHolds(ctx context.Context, now time.Time) (int64, error)
+	CheckIn(ctx context.Context, id uint, staffID uint, at time.Time, audit AuditEntry) (bool, error)
+	CloseFinishedReservations(ctx context.Context, now time.Time) (int64, int64, error)
+	CountNoShows(ctx context.Context, userID uint, since time.Time) (int64, error)
 }
 
 type reservationRepository struct {
@@ -25,15 +37,162 @@ func NewReservationRepository(db *gorm.DB) ReservationRepository {
 	return &reservationRepository{db: db}
 }
 
-func (r *reservationRepository) CreateReservation(ctx context.Context, reservation *models.Reservation) error {
-	return r.db.WithContext(ctx).Create(reservation).Error
+// ErrSlotTaken is returned when another active reservation already overlaps the requested range.
+var ErrSlotTaken = errors.New("selected timeslot is already booked")
+
+// ErrReservationChanged is returned when a reservation no longer is as the caller read it,
+// because another request changed it first.
+var ErrReservationChanged = errors.New("reservation was changed by another request, try again")
+
+// CreateReservation inserts the reservation while holding a per-court lock, so
+// concurrent bookings for the same court are serialized. The reservations_no_overlap
+// exclusion constraint is the final guard if anything slips past the check. A booking
+// made by staff is audited in the same transaction.
+func (r *reservationRepository) CreateReservation(ctx context.Context, reservation *models.Reservation, audit AuditEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+// MoveReservation saves a reservation's new court and time, under the same per-court
+// lock and overlap check as CreateReservation. A closure flag is cleared by the move, and
+// the move is audited in the same transaction.
+func (r *reservationRepository) MoveReservation(ctx context.Context, reservation *models.Reservation, audit AuditEntry) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, reservation.ID); err != nil {
+			return err
+		}
+
+		reservation.ClosureID = nil
+		err := tx.Model(&models.Reservation{}).
+			Where("id = ?", reservation.ID).
+			Updates(map[string]interface{}{
+				"court_id":         reservation.CourtID,
+				"reservation_date": reservation.ReservationDate,
+				"time_slot":        reservation.TimeSlot,
+				"start_at":         reservation.StartAt,
+				"end_at":           reservation.EndAt,
+				"closure_id":       nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+// RescheduleReservation saves a reservation's new court, time and price, replacing its
+// price breakdown, under the same per-court lock and overlap check as CreateReservation.
+// It only applies while the reservation still has expected's status and amounts, so a
+// booking cancelled or re-priced meanwhile is left alone and ErrReservationChanged returned.
+func (r *reservationRepository) RescheduleReservation(ctx context.Context, reservation *models.Reservation, expected *models.Reservation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, reservation.ID); err != nil {
+			return err
+		}
+
+		reservation.ClosureID = nil
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ? AND total_amount = ? AND amount_due = ?",
+				reservation.ID, expected.Status, expected.TotalAmount, expected.AmountDue).
+			Updates(map[string]interface{}{
+				"court_id":         reservation.CourtID,
+				"reservation_date": reservation.ReservationDate,
+				"time_slot":        reservation.TimeSlot,
+				"start_at":         reservation.StartAt,
+				"end_at":           reservation.EndAt,
+				"duration_hours":   reservation.DurationHours,
+				"duration_minutes": reservation.DurationMinutes,
+				"total_amount":     reservation.TotalAmount,
+				"amount_due":       reservation.AmountDue,
+				"closure_id":       nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReservationChanged
+		}
+
+		if err := tx.Where("reservation_id = ?", reservation.ID).Delete(&models.ReservationPriceLine{}).Error; err != nil {
+			return err
+		}
+		for i := range reservation.PriceLines {
+			reservation.PriceLines[i].ID = 0
+			reservation.PriceLines[i].ReservationID = reservation.ID
+		}
+		if len(reservation.PriceLines) == 0 {
+			return nil
+		}
+		return tx.Create(&reservation.PriceLines).Error
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+// claimCourt takes the court's advisory lock for the rest of tx and checks that no other
+// active reservation (other than excludeID) overlaps [startAt, endAt).
+func claimCourt(tx *gorm.DB, courtID uint, startAt, endAt time.Time, excludeID uint) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", courtLockNamespace, courtID).Error; err != nil {
+		return err
+	}
+
+	// Release lapsed holds on this court first so they don't trip the exclusion constraint
+	now := time.Now()
+	err := tx.Model(&models.Reservation{}).
+		Where("court_id = ? AND status = ? AND hold_expires_at <= ?", courtID, "pending", now).
+		Update("status", "expired").Error
+	if err != nil {
+		return err
+	}
+
+	var count int64
+	err = tx.Model(&models.Reservation{}).
+		Scopes(holdingCourt(now)).
+		Where("court_id = ? AND start_at < ? AND end_at > ? AND id <> ?", courtID, endAt, startAt, excludeID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSlotTaken
+	}
+	return nil
+}
+
+// courtLockNamespace keys the advisory locks taken per court.
+const courtLockNamespace = 1001
+
+func isExclusionViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23P01"
 }
 
 func (r *reservationRepository) GetReservationByID(ctx context.Context, id uint) (*models.Reservation, error) {
 	var reservation models.Reservation
 	err := r.db.WithContext(ctx).
 		Preload("User").
-		Preload("Court").
+		Preload("Court.Venue").
+		Preload("PriceLines").
 		First(&reservation, id).Error
 	if err != nil {
 		return nil, err
@@ -54,11 +213,71 @@ func (r *reservationRepository) GetUserReservations(ctx context.Context, userID
 	return reservations, nil
 }
 
+// ReservationFilter narrows the staff reservation listing. Zero fields are ignored.
+type ReservationFilter struct {
+	From     *time.Time // First reservation date
+	To       *time.Time // Last reservation date, inclusive
+	VenueID  uint
+	CourtID  uint
+	Status   string
+	Customer string // Matched against the customer's name and email and the guest name
+	Limit    int
+	Offset   int
+}
+
+// SearchReservations lists reservations of all users matching filter, ordered by start time,
+// together with the total number of matches.
+func (r *reservationRepository) SearchReservations(ctx context.Context, filter ReservationFilter) ([]models.Reservation, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Reservation{}).
+		Joins("JOIN users ON users.id = reservations.user_id")
+
+	if filter.From != nil {
+		query = query.Where("reservations.reservation_date >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("reservations.reservation_date <= ?", *filter.To)
+	}
+	if filter.VenueID != 0 {
+		query = query.Where("reservations.court_id IN (SELECT id FROM courts WHERE venue_id = ?)", filter.VenueID)
+	}
+	if filter.CourtID != 0 {
+		query = query.Where("reservations.court_id = ?", filter.CourtID)
+	}
+	if filter.Status != "" {
+		query = query.Where("reservations.status = ?", filter.Status)
+	}
+	if filter.Customer != "" {
+		pattern := "%" + filter.Customer + "%"
+		query = query.Where("(users.name ILIKE ? OR users.email ILIKE ? OR reservations.guest_name ILIKE ?)",
+			pattern, pattern, pattern)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reservations []models.Reservation
+	err := query.
+		Preload("User").
+		Preload("Court").
+		Order("reservations.start_at ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&reservations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return reservations, total, nil
+}
+
 func (r *reservationRepository) GetReservationsByDateAndCourt(ctx context.Context, date time.Time, courtID uint) ([]models.Reservation, error) {
 	var reservations []models.Reservation
 	err := r.db.WithContext(ctx).
-		Where("reservation_date = ? AND court_id = ? AND status IN (?, ?)",
-			date, courtID, "pending", "confirmed").
+		Scopes(holdingCourt(time.Now())).
+		Where("reservation_date = ? AND court_id = ?", date, courtID).
 		Find(&reservations).Error
 	if err != nil {
 		return nil, err
@@ -73,15 +292,155 @@ func (r *reservationRepository) UpdateReservationStatus(ctx context.Context, id
 		Update("status", status).Error
 }
 
+// TransitionReservationStatus saves the reservation's new status only if it is still
+// fromStatus, with its audit log when given. It reports false when another request
+// changed the reservation first, and nothing is saved then.
+func (r *reservationRepository) TransitionReservationStatus(ctx context.Context, id uint, fromStatus string, toStatus string, audit AuditEntry) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", id, fromStatus).
+			Update("status", toStatus)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		applied = true
+		return createAuditLog(tx, audit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
 func (r *reservationRepository) CheckExistingReservation(ctx context.Context, date time.Time, timeSlot string, courtID uint) (bool, error) {
+	return r.CheckExistingReservationExcept(ctx, date, timeSlot, courtID, 0)
+}
+
+// CheckExistingReservationExcept is CheckExistingReservation ignoring the reservation
+// excludeID, for moving a booking within or next to its current slot.
+func (r *reservationRepository) CheckExistingReservationExcept(ctx context.Context, date time.Time, timeSlot string, courtID uint, excludeID uint) (bool, error) {
+	start, end, err := utils.ParseTimeSlot(timeSlot)
+	if err != nil {
+		return false, err
+	}
+
+	reservations, err := activeReservationsOn(ctx, r.db, date, courtID)
+	if err != nil {
+		return false, err
+	}
+
+	others := reservations[:0]
+	for _, reservation := range reservations {
+		if reservation.ID != excludeID {
+			others = append(others, reservation)
+		}
+	}
+	return overlapsAny(others, start, end), nil
+}
+
+// activeReservationsOn returns the reservations that currently hold a court on the given date.
+func activeReservationsOn(ctx context.Context, db *gorm.DB, date time.Time, courtID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	err := db.WithContext(ctx).
+		Scopes(holdingCourt(time.Now())).
+		Where("court_id = ? AND reservation_date = ?", courtID, date).
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+// holdingCourt limits a query to reservations that block their court at the given time:
+// confirmed and checked-in bookings, and pending bookings whose hold has not lapsed yet.
+func holdingCourt(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(status IN (?, ?) OR (status = ? AND (hold_expires_at IS NULL OR hold_expires_at > ?)))",
+			"confirmed", "checked_in", "pending", now)
+	}
+}
+
+// ExpireStaleHolds marks pending reservations whose hold has lapsed as expired.
+func (r *reservationRepository) ExpireStaleHolds(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Reservation{}).
+		Where("status = ? AND hold_expires_at <= ?", "pending", now).
+		Update("status", "expired")
+	return result.RowsAffected, result.Error
+}
+
+// CheckIn marks a confirmed reservation as checked in, together with its audit log. It
+// reports false when the reservation was no longer confirmed.
+func (r *reservationRepository) CheckIn(ctx context.Context, id uint, staffID uint, at time.Time, audit AuditEntry) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", id, "confirmed").
+			Updates(map[string]interface{}{
+				"status":           "checked_in",
+				"checked_in_at":    at,
+				"checked_in_by_id": staffID,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		applied = true
+		return createAuditLog(tx, audit)
+	})
+	if err != nil {
+		return false, err
+	}
+	return applied, nil
+}
+
+// CloseFinishedReservations settles bookings whose slot has ended: checked-in ones are
+// completed, confirmed ones nobody checked in for become no-shows. Bookings caught by a
+// court closure are left alone, since the venue kept the customer from playing.
+func (r *reservationRepository) CloseFinishedReservations(ctx context.Context, now time.Time) (int64, int64, error) {
+	var completed, noShows int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Reservation{}).
+			Where("status = ? AND end_at <= ?", "checked_in", now).
+			Update("status", "completed")
+		if result.Error != nil {
+			return result.Error
+		}
+		completed = result.RowsAffected
+
+		result = tx.Model(&models.Reservation{}).
+			Where("status = ? AND end_at <= ? AND closure_id IS NULL", "confirmed", now).
+			Update("status", "no_show")
+		if result.Error != nil {
+			return result.Error
+		}
+		noShows = result.RowsAffected
+		return nil
+	})
+	return completed, noShows, err
+}
+
+// CountNoShows counts the user's bookings marked as no-shows that started since the given
+// time. Walk-in bookings are kept under the staff member who made them and do not count.
+func (r *reservationRepository) CountNoShows(ctx context.Context, userID uint, since time.Time) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
 		Model(&models.Reservation{}).
-		Where("court_id = ? AND reservation_date = ? AND time_slot = ? AND status IN (?, ?)",
-			courtID, date, timeSlot, "pending", "confirmed").
+		Where("user_id = ? AND status = ? AND start_at >= ? AND COALESCE(guest_name, '') = ''", userID, "no_show", since).
 		Count(&count).Error
-	if err != nil {
-		return false, err
+	return count, err
+}
+
+// overlapsAny reports whether [start, end) intersects the time slot of any reservation.
+func overlapsAny(reservations []models.Reservation, start, end int) bool {
+	for _, reservation := range reservations {
+		resStart, resEnd, err := utils.ParseTimeSlot(reservation.TimeSlot)
+		if err != nil {
+			continue
+		}
+		if utils.RangesOverlap(start, end, resStart, resEnd) {
+			return true
+		}
 	}
-	return count > 0, nil
+	return false
 }
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"backend/pkg/config"
+	"backend/pkg/database"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// openTestDB connects to the Postgres database in TEST_DATABASE_URL and migrates it.
+// The overlap guarantees rely on Postgres advisory locks and exclusion constraints, so
+// these tests are skipped without one.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	return database.ConnectDB(&config.Config{DatabaseURL: dsn, ReservationHoldTTL: 15 * time.Minute})
+}
+
+func TestCreateReservationConcurrentBookings(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	user := models.User{
+		Name:     "Concurrency Test",
+		Email:    fmt.Sprintf("concurrency-%d@example.com", time.Now().UnixNano()),
+		Password: "-",
+	}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	court := models.Court{Name: "Concurrency Court", Location: "-", PricePerHour: 50000}
+	if err := db.Create(&court).Error; err != nil {
+		t.Fatalf("creating court: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("court_id = ?", court.ID).Delete(&models.Reservation{})
+		db.Delete(&court)
+		db.Delete(&user)
+	})
+
+	repo := NewReservationRepository(db)
+	startAt := time.Now().AddDate(0, 0, 30).Truncate(time.Hour)
+	endAt := startAt.Add(2 * time.Hour)
+	holdExpiresAt := time.Now().Add(15 * time.Minute)
+
+	const attempts = 25
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			results <- repo.CreateReservation(ctx, &models.Reservation{
+				UserID:          user.ID,
+				CourtID:         court.ID,
+				ReservationDate: time.Date(startAt.Year(), startAt.Month(), startAt.Day(), 0, 0, 0, 0, time.UTC),
+				TimeSlot:        fmt.Sprintf("%s-%s", startAt.Format("15:04"), endAt.Format("15:04")),
+				StartAt:         startAt,
+				EndAt:           endAt,
+				DurationMinutes: 120,
+				TotalAmount:     100000,
+				Status:          "pending",
+				HoldExpiresAt:   &holdExpiresAt,
+			}, nil)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	won := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			won++
+		case errors.Is(err, ErrSlotTaken):
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	if won != 1 {
+		t.Fatalf("%d of %d parallel bookings succeeded, want exactly 1", won, attempts)
+	}
+
+	var stored int64
+	db.Model(&models.Reservation{}).Where("court_id = ?", court.ID).Count(&stored)
+	if stored != 1 {
+		t.Fatalf("%d reservations stored for the slot, want 1", stored)
+	}
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSeriesCancelled is returned when a series was already cancelled by another request.
+var ErrSeriesCancelled = errors.New("reservation series is already cancelled")
+
+// SeriesRepository stores recurring bookings together with their occurrences.
+type SeriesRepository interface {
+	CreateSeries(ctx context.Context, series *models.ReservationSeries, reservations []models.Reservation) error
+	GetSeriesByID(ctx context.Context, id uint) (*models.ReservationSeries, error)
+	GetUserSeries(ctx context.Context, userID uint) ([]models.ReservationSeries, error)
+	RescheduleSeries(ctx context.Context, series *models.ReservationSeries, reservations []models.Reservation) error
+	CancelSeries(ctx context.Context, id uint, reservationIDs []uint) ([]uint, error)
+}
+
+type seriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &seriesRepository{db: db}
+}
+
+// CreateSeries inserts the series and every occurrence in one transaction. Each
+// occurrence claims its court like CreateReservation does, so either the whole series
+// is booked or nothing is.
+func (r *seriesRepository) CreateSeries(ctx context.Context, series *models.ReservationSeries, reservations []models.Reservation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(series).Error; err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			reservation := &reservations[i]
+			if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, 0); err != nil {
+				return err
+			}
+			reservation.SeriesID = &series.ID
+			if err := tx.Create(reservation).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+func (r *seriesRepository) GetSeriesByID(ctx context.Context, id uint) (*models.ReservationSeries, error) {
+	var series models.ReservationSeries
+	err := r.db.WithContext(ctx).
+		Preload("Reservations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("start_at ASC, court_id ASC")
+		}).
+		Preload("Reservations.Court.Venue").
+		First(&series, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &series, nil
+}
+
+func (r *seriesRepository) GetUserSeries(ctx context.Context, userID uint) ([]models.ReservationSeries, error) {
+	var series []models.ReservationSeries
+	err := r.db.WithContext(ctx).
+		Preload("Reservations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("start_at ASC, court_id ASC")
+		}).
+		Preload("Reservations.Court.Venue").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&series).Error
+	if err != nil {
+		return nil, err
+	}
+	return series, nil
+}
+
+// RescheduleSeries saves the series' new times and moves the given occurrences, all in
+// one transaction and under the same per-court lock as MoveReservation.
+func (r *seriesRepository) RescheduleSeries(ctx context.Context, series *models.ReservationSeries, reservations []models.Reservation) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.ReservationSeries{}).
+			Where("id = ?", series.ID).
+			Updates(map[string]interface{}{
+				"start_time": series.StartTime,
+				"end_time":   series.EndTime,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range reservations {
+			reservation := &reservations[i]
+			if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, reservation.ID); err != nil {
+				return err
+			}
+
+			reservation.ClosureID = nil
+			err := tx.Model(&models.Reservation{}).
+				Where("id = ?", reservation.ID).
+				Updates(map[string]interface{}{
+					"time_slot":  reservation.TimeSlot,
+					"start_at":   reservation.StartAt,
+					"end_at":     reservation.EndAt,
+					"closure_id": nil,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	return err
+}
+
+// CancelSeries cancels the series and those of the given occurrences that are still
+// pending or confirmed, together. It returns the IDs of the occurrences that were
+// confirmed, the ones owed a refund, or ErrSeriesCancelled when another request cancelled
+// the series first.
+func (r *seriesRepository) CancelSeries(ctx context.Context, id uint, reservationIDs []uint) ([]uint, error) {
+	var confirmedIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ReservationSeries{}).
+			Where("id = ? AND status <> ?", id, "cancelled").
+			Update("status", "cancelled")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSeriesCancelled
+		}
+		if len(reservationIDs) == 0 {
+			return nil
+		}
+
+		var confirmed []models.Reservation
+		err := tx.Model(&confirmed).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("id IN ? AND series_id = ? AND status = ?", reservationIDs, id, "confirmed").
+			Update("status", "cancelled").Error
+		if err != nil {
+			return err
+		}
+		for _, reservation := range confirmed {
+			confirmedIDs = append(confirmedIDs, reservation.ID)
+		}
+
+		return tx.Model(&models.Reservation{}).
+			Where("id IN ? AND series_id = ? AND status = ?", reservationIDs, id, "pending").
+			Update("status", "cancelled").Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return confirmedIDs, nil
+}
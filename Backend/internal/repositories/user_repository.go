@@ -12,6 +12,7 @@ type UserRepository interface {
 	GetUserByEmail(ctx context.Context, email string) (*models.User, error)
 	GetUserByID(ctx context.Context, id uint) (*models.User, error)
 	CheckEmailExists(ctx context.Context, email string) (bool, error)
+	UpdateUserRole(ctx context.Context, id uint, role string, venueID *uint, audit AuditEntry) error
 }
 
 type userRepository struct {
@@ -52,3 +53,18 @@ func (r *userRepository) CheckEmailExists(ctx context.Context, email string) (bo
 	}
 	return count > 0, nil
 }
+
+func (r *userRepository) UpdateUserRole(ctx context.Context, id uint, role string, venueID *uint, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.User{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"role":     role,
+				"venue_id": venueID,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+}
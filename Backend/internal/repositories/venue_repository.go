@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type VenueRepository interface {
+	GetActiveVenues(ctx context.Context) ([]models.Venue, error)
+	ListVenues(ctx context.Context) ([]models.Venue, error)
+	GetVenueByID(ctx context.Context, id uint) (*models.Venue, error)
+	CreateVenue(ctx context.Context, venue *models.Venue, audit AuditEntry) error
+	UpdateVenue(ctx context.Context, venue *models.Venue, audit AuditEntry) error
+}
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) VenueRepository {
+	return &venueRepository{db: db}
+}
+
+func (r *venueRepository) GetActiveVenues(ctx context.Context) ([]models.Venue, error) {
+	var venues []models.Venue
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "active").
+		Order("name ASC").
+		Find(&venues).Error
+	if err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+// ListVenues returns every venue regardless of status, for administration.
+func (r *venueRepository) ListVenues(ctx context.Context) ([]models.Venue, error) {
+	var venues []models.Venue
+	err := r.db.WithContext(ctx).Order("id ASC").Find(&venues).Error
+	if err != nil {
+		return nil, err
+	}
+	return venues, nil
+}
+
+func (r *venueRepository) GetVenueByID(ctx context.Context, id uint) (*models.Venue, error) {
+	var venue models.Venue
+	err := r.db.WithContext(ctx).First(&venue, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) CreateVenue(ctx context.Context, venue *models.Venue, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(venue).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+}
+
+func (r *venueRepository) UpdateVenue(ctx context.Context, venue *models.Venue, audit AuditEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(venue).Error; err != nil {
+			return err
+		}
+		return createAuditLog(tx, audit)
+	})
+}
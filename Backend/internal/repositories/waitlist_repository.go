@@ -0,0 +1,226 @@
+package repositories
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrWaitlistEntryChanged is returned when an entry is no longer in the state an update expects.
+var ErrWaitlistEntryChanged = errors.New("waitlist entry changed concurrently")
+
+type WaitlistRepository interface {
+	CreateEntry(ctx context.Context, entry *models.WaitlistEntry) error
+	GetEntryByID(ctx context.Context, id uint) (*models.WaitlistEntry, error)
+	GetUserEntries(ctx context.Context, userID uint) ([]models.WaitlistEntry, error)
+	GetActiveEntriesOn(ctx context.Context, courtID uint, date time.Time) ([]models.WaitlistEntry, error)
+	GetWaitingEntries(ctx context.Context, now time.Time) ([]models.WaitlistEntry, error)
+	OfferEntry(ctx context.Context, entry *models.WaitlistEntry, reservation *models.Reservation, offerExpiresAt time.Time) error
+	ClaimOffer(ctx context.Context, entry *models.WaitlistEntry, now, holdExpiresAt time.Time) error
+	CancelEntry(ctx context.Context, entry *models.WaitlistEntry) error
+	ExpireEntries(ctx context.Context, now time.Time) (int64, error)
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) CreateEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *waitlistRepository) GetEntryByID(ctx context.Context, id uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Preload("Court").
+		First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) GetUserEntries(ctx context.Context, userID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Preload("Court").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetActiveEntriesOn returns the waiting and offered entries for a court on date, in
+// queue order.
+func (r *waitlistRepository) GetActiveEntriesOn(ctx context.Context, courtID uint, date time.Time) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("court_id = ? AND reservation_date = ? AND status IN (?, ?)", courtID, date, "waiting", "offered").
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// GetWaitingEntries returns every entry still waiting for a slot that has not started, in
+// queue order.
+func (r *waitlistRepository) GetWaitingEntries(ctx context.Context, now time.Time) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND start_at > ?", "waiting", now).
+		Order("created_at ASC, id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// OfferEntry books the held reservation for a waiting entry and marks the entry offered,
+// in one transaction. The court is claimed like CreateReservation does.
+func (r *waitlistRepository) OfferEntry(ctx context.Context, entry *models.WaitlistEntry, reservation *models.Reservation, offerExpiresAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := claimCourt(tx, reservation.CourtID, reservation.StartAt, reservation.EndAt, 0); err != nil {
+			return err
+		}
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, "waiting").
+			Updates(map[string]interface{}{
+				"status":           "offered",
+				"reservation_id":   reservation.ID,
+				"offer_expires_at": offerExpiresAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWaitlistEntryChanged
+		}
+		return nil
+	})
+
+	if isExclusionViolation(err) {
+		return ErrSlotTaken
+	}
+	if err != nil {
+		return err
+	}
+
+	entry.Status = "offered"
+	entry.ReservationID = &reservation.ID
+	entry.OfferExpiresAt = &offerExpiresAt
+	return nil
+}
+
+// ClaimOffer accepts an open offer: the entry becomes claimed and the held reservation
+// gets the regular payment window.
+func (r *waitlistRepository) ClaimOffer(ctx context.Context, entry *models.WaitlistEntry, now, holdExpiresAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ? AND offer_expires_at > ?", entry.ID, "offered", now).
+			Update("status", "claimed")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWaitlistEntryChanged
+		}
+
+		result = tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", entry.ReservationID, "pending").
+			Update("hold_expires_at", holdExpiresAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWaitlistEntryChanged
+		}
+		return nil
+	})
+}
+
+// CancelEntry takes a waiting or offered entry off the waitlist. An offered slot is
+// released by cancelling its held reservation.
+func (r *waitlistRepository) CancelEntry(ctx context.Context, entry *models.WaitlistEntry) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("id = ? AND status = ?", entry.ID, entry.Status).
+			Update("status", "cancelled")
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrWaitlistEntryChanged
+		}
+
+		if entry.Status != "offered" || entry.ReservationID == nil {
+			return nil
+		}
+		return tx.Model(&models.Reservation{}).
+			Where("id = ? AND status = ?", *entry.ReservationID, "pending").
+			Update("status", "cancelled").Error
+	})
+}
+
+// ExpireEntries expires entries whose slot has started and offers that were not claimed
+// in time; the reservations held for those offers are released with them.
+func (r *waitlistRepository) ExpireEntries(ctx context.Context, now time.Time) (int64, error) {
+	var expired int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.WaitlistEntry{}).
+			Where("status = ? AND start_at <= ?", "waiting", now).
+			Update("status", "expired")
+		if result.Error != nil {
+			return result.Error
+		}
+		expired += result.RowsAffected
+
+		var lapsed []models.WaitlistEntry
+		err := tx.Where("status = ? AND offer_expires_at <= ?", "offered", now).
+			Find(&lapsed).Error
+		if err != nil || len(lapsed) == 0 {
+			return err
+		}
+
+		entryIDs := make([]uint, 0, len(lapsed))
+		reservationIDs := make([]uint, 0, len(lapsed))
+		for _, entry := range lapsed {
+			entryIDs = append(entryIDs, entry.ID)
+			if entry.ReservationID != nil {
+				reservationIDs = append(reservationIDs, *entry.ReservationID)
+			}
+		}
+
+		result = tx.Model(&models.WaitlistEntry{}).
+			Where("id IN ?", entryIDs).
+			Update("status", "expired")
+		if result.Error != nil {
+			return result.Error
+		}
+		expired += result.RowsAffected
+
+		if len(reservationIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Reservation{}).
+			Where("id IN ? AND status = ?", reservationIDs, "pending").
+			Update("status", "expired").Error
+	})
+	return expired, err
+}
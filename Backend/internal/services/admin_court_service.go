@@ -0,0 +1,313 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrCourtNotFound is returned when the court does not exist.
+var ErrCourtNotFound = errors.New("court not found")
+
+// defaultCourtCapacity is a doubles game.
+const defaultCourtCapacity = 4
+
+// AdminCourtService creates and edits courts; every change is written to the audit log
+// in the same transaction.
+// Staff manage only the courts of their own venue.
+type AdminCourtService interface {
+	ListCourts(ctx context.Context, actorID uint) ([]models.Court, error)
+	GetCourt(ctx context.Context, actorID uint, id uint) (*models.AdminCourtResponse, error)
+	CreateCourt(ctx context.Context, actorID uint, req *models.CreateCourtRequest) (*models.AdminCourtResponse, error)
+	UpdateCourt(ctx context.Context, actorID uint, id uint, req *models.UpdateCourtRequest) (*models.AdminCourtResponse, error)
+	DeactivateCourt(ctx context.Context, actorID uint, id uint) (*models.AdminCourtResponse, error)
+	SetOperatingHours(ctx context.Context, actorID uint, id uint, req *models.SetOperatingHoursRequest) (*models.AdminCourtResponse, error)
+}
+
+type adminCourtService struct {
+	courtRepo repositories.CourtRepository
+	venueRepo repositories.VenueRepository
+	userRepo  repositories.UserRepository
+}
+
+func NewAdminCourtService(
+	courtRepo repositories.CourtRepository,
+	venueRepo repositories.VenueRepository,
+	userRepo repositories.UserRepository,
+) AdminCourtService {
+	return &adminCourtService{
+		courtRepo: courtRepo,
+		venueRepo: venueRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *adminCourtService) ListCourts(ctx context.Context, actorID uint) ([]models.Court, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	courts, err := s.courtRepo.ListCourts(ctx, scope)
+	if err != nil {
+		return nil, errors.New("failed to get courts")
+	}
+	return courts, nil
+}
+
+func (s *adminCourtService) GetCourt(ctx context.Context, actorID uint, id uint) (*models.AdminCourtResponse, error) {
+	if _, err := s.scopedCourt(ctx, actorID, id); err != nil {
+		return nil, err
+	}
+	return s.courtWithHours(ctx, id)
+}
+
+// scopedCourt loads the court and checks that it is within the actor's venue.
+func (s *adminCourtService) scopedCourt(ctx context.Context, actorID uint, id uint) (*models.Court, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	court, err := s.courtRepo.GetCourtByID(ctx, id)
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+	if err := checkVenue(scope, court.VenueID); err != nil {
+		return nil, err
+	}
+	return court, nil
+}
+
+func (s *adminCourtService) courtWithHours(ctx context.Context, id uint) (*models.AdminCourtResponse, error) {
+	court, err := s.courtRepo.GetCourtByID(ctx, id)
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+
+	hours, err := s.courtRepo.GetWeeklyHours(ctx, id)
+	if err != nil {
+		return nil, errors.New("failed to get operating hours")
+	}
+
+	return &models.AdminCourtResponse{Court: *court, Hours: hours}, nil
+}
+
+func (s *adminCourtService) CreateCourt(ctx context.Context, actorID uint, req *models.CreateCourtRequest) (*models.AdminCourtResponse, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkVenue(scope, &req.VenueID); err != nil {
+		return nil, err
+	}
+	if _, err := s.venueRepo.GetVenueByID(ctx, req.VenueID); err != nil {
+		return nil, ErrVenueNotFound
+	}
+
+	hours, err := operatingHoursFrom(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	court := &models.Court{
+		VenueID:               &req.VenueID,
+		Name:                  req.Name,
+		Location:              req.Location,
+		PricePerHour:          req.PricePerHour,
+		Status:                valueOr(req.Status, "active"),
+		SlotMinutes:           valueOr(req.SlotMinutes, defaultSlotMinutes),
+		FullRefundHours:       derefOr(req.FullRefundHours, 24),
+		PartialRefundHours:    derefOr(req.PartialRefundHours, 2),
+		PartialRefundPercent:  derefOr(req.PartialRefundPercent, 50),
+		RescheduleCutoffHours: derefOr(req.RescheduleCutoffHours, 12),
+		Description:           req.Description,
+		Environment:           valueOr(req.Environment, "indoor"),
+		FloorType:             req.FloorType,
+		Lighting:              req.Lighting,
+		HasAC:                 req.HasAC,
+		Capacity:              valueOr(req.Capacity, defaultCourtCapacity),
+		Photos:                req.Photos,
+	}
+	if err := validateRefundPolicy(court); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "court.create", "court", court.ID, nil, &models.AdminCourtResponse{Court: *court, Hours: hours})
+	}
+	if err := s.courtRepo.CreateCourt(ctx, court, hours, audit); err != nil {
+		return nil, fmt.Errorf("failed to create court: %v", err)
+	}
+	return &models.AdminCourtResponse{Court: *court, Hours: hours}, nil
+}
+
+func (s *adminCourtService) UpdateCourt(ctx context.Context, actorID uint, id uint, req *models.UpdateCourtRequest) (*models.AdminCourtResponse, error) {
+	court, err := s.scopedCourt(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *court
+
+	if req.VenueID != nil {
+		// Staff cannot hand a court over to another venue
+		if court.VenueID == nil || *req.VenueID != *court.VenueID {
+			scope, err := staffVenue(ctx, s.userRepo, actorID)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkVenue(scope, req.VenueID); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := s.venueRepo.GetVenueByID(ctx, *req.VenueID); err != nil {
+			return nil, ErrVenueNotFound
+		}
+		court.VenueID = req.VenueID
+	}
+	if req.Name != nil {
+		court.Name = *req.Name
+	}
+	if req.Location != nil {
+		court.Location = *req.Location
+	}
+	if req.PricePerHour != nil {
+		court.PricePerHour = *req.PricePerHour
+	}
+	if req.Status != nil {
+		court.Status = *req.Status
+	}
+	if req.SlotMinutes != nil {
+		court.SlotMinutes = *req.SlotMinutes
+	}
+	if req.FullRefundHours != nil {
+		court.FullRefundHours = *req.FullRefundHours
+	}
+	if req.PartialRefundHours != nil {
+		court.PartialRefundHours = *req.PartialRefundHours
+	}
+	if req.PartialRefundPercent != nil {
+		court.PartialRefundPercent = *req.PartialRefundPercent
+	}
+	if req.RescheduleCutoffHours != nil {
+		court.RescheduleCutoffHours = *req.RescheduleCutoffHours
+	}
+	if req.Description != nil {
+		court.Description = *req.Description
+	}
+	if req.Environment != nil {
+		court.Environment = *req.Environment
+	}
+	if req.FloorType != nil {
+		court.FloorType = *req.FloorType
+	}
+	if req.Lighting != nil {
+		court.Lighting = *req.Lighting
+	}
+	if req.HasAC != nil {
+		court.HasAC = *req.HasAC
+	}
+	if req.Capacity != nil {
+		court.Capacity = *req.Capacity
+	}
+	if req.Photos != nil {
+		court.Photos = req.Photos
+	}
+	if err := validateRefundPolicy(court); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "court.update", "court", court.ID, before, court)
+	}
+	if err := s.courtRepo.UpdateCourt(ctx, court, audit); err != nil {
+		return nil, fmt.Errorf("failed to update court: %v", err)
+	}
+
+	return s.courtWithHours(ctx, id)
+}
+
+// DeactivateCourt hides the court from customers. Courts are never deleted, so past
+// reservations keep their court.
+func (s *adminCourtService) DeactivateCourt(ctx context.Context, actorID uint, id uint) (*models.AdminCourtResponse, error) {
+	status := "inactive"
+	return s.UpdateCourt(ctx, actorID, id, &models.UpdateCourtRequest{Status: &status})
+}
+
+// SetOperatingHours replaces the court's weekly schedule.
+func (s *adminCourtService) SetOperatingHours(ctx context.Context, actorID uint, id uint, req *models.SetOperatingHoursRequest) (*models.AdminCourtResponse, error) {
+	if _, err := s.scopedCourt(ctx, actorID, id); err != nil {
+		return nil, err
+	}
+	before, err := s.courtWithHours(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := operatingHoursFrom(req.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "court.hours", "court", id, before.Hours, hours)
+	}
+	if err := s.courtRepo.ReplaceWeeklyHours(ctx, id, hours, audit); err != nil {
+		return nil, fmt.Errorf("failed to save operating hours: %v", err)
+	}
+
+	return s.courtWithHours(ctx, id)
+}
+
+// operatingHoursFrom validates the requested weekly schedule: one entry per weekday,
+// and a valid opening range unless the day is closed.
+func operatingHoursFrom(requests []models.OperatingHoursRequest) ([]models.CourtOperatingHours, error) {
+	seen := make(map[int]bool)
+	hours := make([]models.CourtOperatingHours, 0, len(requests))
+	for _, req := range requests {
+		weekday := *req.Weekday
+		if seen[weekday] {
+			return nil, fmt.Errorf("weekday %d is listed more than once", weekday)
+		}
+		seen[weekday] = true
+
+		if !req.Closed {
+			if _, _, err := utils.ParseTimeSlot(req.OpenTime + "-" + req.CloseTime); err != nil {
+				return nil, fmt.Errorf("invalid hours for weekday %d: %v", weekday, err)
+			}
+		}
+
+		hours = append(hours, models.CourtOperatingHours{
+			Weekday:   weekday,
+			OpenTime:  req.OpenTime,
+			CloseTime: req.CloseTime,
+			Closed:    req.Closed,
+		})
+	}
+	return hours, nil
+}
+
+func validateRefundPolicy(court *models.Court) error {
+	if court.PartialRefundHours > court.FullRefundHours {
+		return errors.New("partial_refund_hours cannot exceed full_refund_hours")
+	}
+	return nil
+}
+
+func valueOr[T comparable](value, fallback T) T {
+	var zero T
+	if value == zero {
+		return fallback
+	}
+	return value
+}
+
+func derefOr[T any](value *T, fallback T) T {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPricingRuleNotFound = errors.New("pricing rule not found")
+	ErrHolidayNotFound     = errors.New("holiday not found")
+	ErrPricingRuleOverlap  = repositories.ErrPricingRuleOverlap
+	ErrHolidayExists       = repositories.ErrHolidayExists
+)
+
+// AdminPricingService manages courts' pricing rules and the holiday calendar; every change
+// is written to the audit log in the same transaction. Rules are validated when saved,
+// so quotes never meet a malformed band.
+type AdminPricingService interface {
+	ListRules(ctx context.Context, courtID uint) ([]models.PricingRule, error)
+	CreateRule(ctx context.Context, actorID uint, req *models.CreatePricingRuleRequest) (*models.PricingRule, error)
+	UpdateRule(ctx context.Context, actorID uint, id uint, req *models.UpdatePricingRuleRequest) (*models.PricingRule, error)
+	DeleteRule(ctx context.Context, actorID uint, id uint) error
+
+	ListHolidays(ctx context.Context) ([]models.Holiday, error)
+	CreateHoliday(ctx context.Context, actorID uint, req *models.CreateHolidayRequest) (*models.Holiday, error)
+	UpdateHoliday(ctx context.Context, actorID uint, id uint, req *models.UpdateHolidayRequest) (*models.Holiday, error)
+	DeleteHoliday(ctx context.Context, actorID uint, id uint) error
+}
+
+type adminPricingService struct {
+	pricingRepo repositories.PricingRepository
+	courtRepo   repositories.CourtRepository
+}
+
+func NewAdminPricingService(
+	pricingRepo repositories.PricingRepository,
+	courtRepo repositories.CourtRepository,
+) AdminPricingService {
+	return &adminPricingService{
+		pricingRepo: pricingRepo,
+		courtRepo:   courtRepo,
+	}
+}
+
+func (s *adminPricingService) ListRules(ctx context.Context, courtID uint) ([]models.PricingRule, error) {
+	if _, err := s.courtRepo.GetCourtByID(ctx, courtID); err != nil {
+		return nil, ErrCourtNotFound
+	}
+
+	rules, err := s.pricingRepo.ListRules(ctx, courtID)
+	if err != nil {
+		return nil, errors.New("failed to load pricing rules")
+	}
+	return rules, nil
+}
+
+func (s *adminPricingService) CreateRule(ctx context.Context, actorID uint, req *models.CreatePricingRuleRequest) (*models.PricingRule, error) {
+	if _, err := s.courtRepo.GetCourtByID(ctx, req.CourtID); err != nil {
+		return nil, ErrCourtNotFound
+	}
+
+	rule := &models.PricingRule{
+		CourtID:      req.CourtID,
+		Name:         req.Name,
+		DayType:      req.DayType,
+		StartTime:    req.StartTime,
+		EndTime:      req.EndTime,
+		PricePerHour: req.PricePerHour,
+		Priority:     req.Priority,
+	}
+	var err error
+	if rule.StartDate, err = optionalDate(req.StartDate, "start_date"); err != nil {
+		return nil, err
+	}
+	if rule.EndDate, err = optionalDate(req.EndDate, "end_date"); err != nil {
+		return nil, err
+	}
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "pricing_rule.create", "pricing_rule", rule.ID, nil, rule)
+	}
+	if err := s.saveRule(ctx, rule, audit); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *adminPricingService) UpdateRule(ctx context.Context, actorID uint, id uint, req *models.UpdatePricingRuleRequest) (*models.PricingRule, error) {
+	rule, err := s.pricingRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, ErrPricingRuleNotFound
+	}
+	before := *rule
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.DayType != nil {
+		rule.DayType = *req.DayType
+	}
+	if req.StartTime != nil {
+		rule.StartTime = *req.StartTime
+	}
+	if req.EndTime != nil {
+		rule.EndTime = *req.EndTime
+	}
+	if req.StartDate != nil {
+		if rule.StartDate, err = optionalDate(*req.StartDate, "start_date"); err != nil {
+			return nil, err
+		}
+	}
+	if req.EndDate != nil {
+		if rule.EndDate, err = optionalDate(*req.EndDate, "end_date"); err != nil {
+			return nil, err
+		}
+	}
+	if req.PricePerHour != nil {
+		rule.PricePerHour = *req.PricePerHour
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if err := validatePricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "pricing_rule.update", "pricing_rule", rule.ID, before, rule)
+	}
+	if err := s.saveRule(ctx, rule, audit); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *adminPricingService) saveRule(ctx context.Context, rule *models.PricingRule, audit repositories.AuditEntry) error {
+	err := s.pricingRepo.SaveRule(ctx, rule, audit)
+	if errors.Is(err, ErrPricingRuleOverlap) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save pricing rule: %v", err)
+	}
+	return nil
+}
+
+func (s *adminPricingService) DeleteRule(ctx context.Context, actorID uint, id uint) error {
+	rule, err := s.pricingRepo.GetRuleByID(ctx, id)
+	if err != nil {
+		return ErrPricingRuleNotFound
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "pricing_rule.delete", "pricing_rule", id, rule, nil)
+	}
+	err = s.pricingRepo.DeleteRule(ctx, id, audit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrPricingRuleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete pricing rule: %v", err)
+	}
+	return nil
+}
+
+// validatePricingRule checks a rule before it is saved: a known day type, both times of
+// the band or neither, an ordered date range and a non-negative rate. Times are stored
+// normalised as "HH:MM".
+func validatePricingRule(rule *models.PricingRule) error {
+	switch rule.DayType {
+	case "", "weekday", "weekend", "holiday":
+	default:
+		return fmt.Errorf("invalid day_type %q. Use weekday, weekend or holiday", rule.DayType)
+	}
+
+	if rule.StartTime != "" || rule.EndTime != "" {
+		start, end, err := utils.ParseTimeSlot(rule.StartTime + "-" + rule.EndTime)
+		if err != nil {
+			return fmt.Errorf("invalid time band: %v", err)
+		}
+		rule.StartTime = utils.FormatClock(start)
+		rule.EndTime = utils.FormatClock(end)
+	}
+
+	if rule.StartDate != nil && rule.EndDate != nil && rule.EndDate.Before(*rule.StartDate) {
+		return errors.New("end_date cannot be before start_date")
+	}
+	if rule.PricePerHour < 0 {
+		return errors.New("price_per_hour cannot be negative")
+	}
+	return nil
+}
+
+func (s *adminPricingService) ListHolidays(ctx context.Context) ([]models.Holiday, error) {
+	holidays, err := s.pricingRepo.ListHolidays(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get holidays")
+	}
+	return holidays, nil
+}
+
+func (s *adminPricingService) CreateHoliday(ctx context.Context, actorID uint, req *models.CreateHolidayRequest) (*models.Holiday, error) {
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+
+	holiday := &models.Holiday{Date: date, Name: req.Name}
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "holiday.create", "holiday", holiday.ID, nil, holiday)
+	}
+	if err := s.saveHoliday(ctx, holiday, audit); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *adminPricingService) UpdateHoliday(ctx context.Context, actorID uint, id uint, req *models.UpdateHolidayRequest) (*models.Holiday, error) {
+	holiday, err := s.pricingRepo.GetHolidayByID(ctx, id)
+	if err != nil {
+		return nil, ErrHolidayNotFound
+	}
+	before := *holiday
+
+	if req.Date != nil {
+		date, err := time.Parse("2006-01-02", *req.Date)
+		if err != nil {
+			return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+		}
+		holiday.Date = date
+	}
+	if req.Name != nil {
+		holiday.Name = *req.Name
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "holiday.update", "holiday", holiday.ID, before, holiday)
+	}
+	if err := s.saveHoliday(ctx, holiday, audit); err != nil {
+		return nil, err
+	}
+	return holiday, nil
+}
+
+func (s *adminPricingService) saveHoliday(ctx context.Context, holiday *models.Holiday, audit repositories.AuditEntry) error {
+	err := s.pricingRepo.SaveHoliday(ctx, holiday, audit)
+	if errors.Is(err, ErrHolidayExists) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save holiday: %v", err)
+	}
+	return nil
+}
+
+func (s *adminPricingService) DeleteHoliday(ctx context.Context, actorID uint, id uint) error {
+	holiday, err := s.pricingRepo.GetHolidayByID(ctx, id)
+	if err != nil {
+		return ErrHolidayNotFound
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "holiday.delete", "holiday", id, holiday, nil)
+	}
+	err = s.pricingRepo.DeleteHoliday(ctx, id, audit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrHolidayNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %v", err)
+	}
+	return nil
+}
+
+// optionalDate parses a YYYY-MM-DD field; an empty value means no date.
+func optionalDate(value, field string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s format. Use YYYY-MM-DD", field)
+	}
+	return &date, nil
+}
@@ -0,0 +1,395 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Page size of the staff reservation listing.
+const (
+	defaultReservationPageSize = 50
+	maxReservationPageSize     = 200
+)
+
+// AdminReservationService is the front desk console: it sees every customer's bookings
+// and can override them. Staff are limited to the courts of their own venue; admins see
+// every venue. Every override is written to the audit log in the same transaction.
+type AdminReservationService interface {
+	ListReservations(ctx context.Context, actorID uint, filter repositories.ReservationFilter) ([]models.AdminReservationResponse, int64, error)
+	GetReservation(ctx context.Context, actorID uint, id uint) (*models.AdminReservationResponse, error)
+	CreateReservation(ctx context.Context, actorID uint, req *models.AdminCreateReservationRequest) (*models.AdminReservationResponse, error)
+	RecordCashPayment(ctx context.Context, actorID uint, id uint) (*models.Payment, error)
+	CancelReservation(ctx context.Context, actorID uint, id uint, req *models.ForceCancelReservationRequest) ([]models.Refund, error)
+	MoveReservation(ctx context.Context, actorID uint, id uint, req *models.MoveReservationRequest) (*models.AdminReservationResponse, error)
+}
+
+type adminReservationService struct {
+	reservationRepo repositories.ReservationRepository
+	courtRepo       repositories.CourtRepository
+	closureRepo     repositories.ClosureRepository
+	userRepo        repositories.UserRepository
+	paymentRepo     repositories.PaymentRepository
+	refundRepo      repositories.RefundRepository
+	midtransService MidtransService
+	pricingService  PricingService
+	holdTTL         time.Duration
+}
+
+func NewAdminReservationService(
+	reservationRepo repositories.ReservationRepository,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	userRepo repositories.UserRepository,
+	paymentRepo repositories.PaymentRepository,
+	refundRepo repositories.RefundRepository,
+	midtransService MidtransService,
+	pricingService PricingService,
+	holdTTL time.Duration,
+) AdminReservationService {
+	return &adminReservationService{
+		reservationRepo: reservationRepo,
+		courtRepo:       courtRepo,
+		closureRepo:     closureRepo,
+		userRepo:        userRepo,
+		paymentRepo:     paymentRepo,
+		refundRepo:      refundRepo,
+		midtransService: midtransService,
+		pricingService:  pricingService,
+		holdTTL:         holdTTL,
+	}
+}
+
+func toAdminReservationResponse(reservation *models.Reservation) models.AdminReservationResponse {
+	return models.AdminReservationResponse{
+		ReservationResponse: toReservationResponse(reservation),
+		CustomerName:        reservation.User.Name,
+		CustomerEmail:       reservation.User.Email,
+		GuestName:           reservation.GuestName,
+		GuestPhone:          reservation.GuestPhone,
+		CreatedByID:         reservation.CreatedByID,
+	}
+}
+
+func (s *adminReservationService) ListReservations(ctx context.Context, actorID uint, filter repositories.ReservationFilter) ([]models.AdminReservationResponse, int64, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if scope != nil {
+		filter.VenueID = *scope
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxReservationPageSize {
+		filter.Limit = defaultReservationPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	reservations, total, err := s.reservationRepo.SearchReservations(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.New("failed to get reservations")
+	}
+
+	responses := make([]models.AdminReservationResponse, 0, len(reservations))
+	for i := range reservations {
+		responses = append(responses, toAdminReservationResponse(&reservations[i]))
+	}
+	return responses, total, nil
+}
+
+func (s *adminReservationService) GetReservation(ctx context.Context, actorID uint, id uint) (*models.AdminReservationResponse, error) {
+	reservation, _, err := s.loadReservation(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toAdminReservationResponse(reservation)
+	return &response, nil
+}
+
+// loadReservation fetches a reservation the actor may manage, along with the actor's venue scope.
+func (s *adminReservationService) loadReservation(ctx context.Context, actorID uint, id uint) (*models.Reservation, *uint, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, id)
+	if err != nil {
+		return nil, nil, ErrReservationNotFound
+	}
+	if err := checkVenue(scope, reservation.Court.VenueID); err != nil {
+		return nil, nil, err
+	}
+	return reservation, scope, nil
+}
+
+// CreateReservation books for a registered customer or a walk-in guest. Walk-ins are
+// kept under the staff member's own account with the guest's name on the booking.
+func (s *adminReservationService) CreateReservation(ctx context.Context, actorID uint, req *models.AdminCreateReservationRequest) (*models.AdminReservationResponse, error) {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+	court, err := s.courtRepo.GetCourtByID(ctx, req.CourtID)
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+	if err := checkVenue(scope, court.VenueID); err != nil {
+		return nil, err
+	}
+
+	userID := actorID
+	switch {
+	case req.UserID != nil:
+		if _, err := s.userRepo.GetUserByID(ctx, *req.UserID); err != nil {
+			return nil, errors.New("customer not found")
+		}
+		userID = *req.UserID
+	case req.GuestName == "":
+		return nil, errors.New("provide user_id for a customer or guest_name for a walk-in")
+	}
+
+	reservation, err := quoteReservation(ctx, s.courtRepo, s.closureRepo, s.reservationRepo, s.pricingService, &req.CreateReservationRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	holdExpiresAt := time.Now().Add(s.holdTTL)
+	reservation.UserID = userID
+	reservation.Status = "pending"
+	reservation.HoldExpiresAt = &holdExpiresAt
+	reservation.GuestName = req.GuestName
+	reservation.GuestPhone = req.GuestPhone
+	reservation.CreatedByID = &actorID
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "reservation.create", "reservation", reservation.ID, nil, toAdminReservationResponse(reservation))
+	}
+	err = s.reservationRepo.CreateReservation(ctx, reservation, audit)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, errors.New("failed to create reservation")
+	}
+
+	if req.CashPaid {
+		if _, err := s.RecordCashPayment(ctx, actorID, reservation.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.GetReservation(ctx, actorID, reservation.ID)
+	if err != nil {
+		return nil, errors.New("failed to fetch created reservation")
+	}
+	return created, nil
+}
+
+// RecordCashPayment settles a pending reservation paid in cash at the front desk and
+// confirms it, together, so a booking that expired meanwhile is neither revived nor paid.
+func (s *adminReservationService) RecordCashPayment(ctx context.Context, actorID uint, id uint) (*models.Payment, error) {
+	reservation, _, err := s.loadReservation(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.Status != "pending" {
+		return nil, ErrReservationNotPayable
+	}
+	if reservation.HoldExpiresAt != nil && !reservation.HoldExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: reservation hold has expired", ErrReservationNotPayable)
+	}
+	if reservation.SeriesID != nil {
+		return nil, fmt.Errorf("%w: reservation is part of series %d", ErrReservationNotPayable, *reservation.SeriesID)
+	}
+	if reservation.SplitDeadline != nil {
+		return nil, fmt.Errorf("%w: reservation is being paid in shares by its players", ErrReservationNotPayable)
+	}
+	if _, err := s.paymentRepo.GetActivePaymentByReservationID(ctx, id); err == nil {
+		return nil, errors.New("reservation already has an active payment")
+	}
+
+	now := time.Now()
+	payment := &models.Payment{
+		ReservationID:   id,
+		Amount:          reservation.TotalAmount,
+		Status:          "paid",
+		PaymentMethod:   "cash",
+		MidtransOrderID: fmt.Sprintf("CASH-%d-%d", id, now.Unix()),
+		PaymentTime:     now,
+	}
+	// The payment is only kept if the booking is still held when it is confirmed
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "reservation.cash_payment", "reservation", id, nil, payment)
+	}
+	confirmed, err := s.paymentRepo.CreateSettledReservationPayment(ctx, payment, now, audit)
+	if err != nil {
+		return nil, errors.New("failed to save payment")
+	}
+	if !confirmed {
+		return nil, fmt.Errorf("%w: reservation is no longer held", ErrReservationNotPayable)
+	}
+	return payment, nil
+}
+
+// CancelReservation cancels a pending or confirmed booking regardless of the cancellation
+// policy. With req.Refund, a paid booking is refunded in full, less the channel fee.
+func (s *adminReservationService) CancelReservation(ctx context.Context, actorID uint, id uint, req *models.ForceCancelReservationRequest) ([]models.Refund, error) {
+	reservation, _, err := s.loadReservation(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != "pending" && reservation.Status != "confirmed" {
+		return nil, errors.New("only pending or confirmed reservations can be cancelled")
+	}
+	before := toAdminReservationResponse(reservation)
+	after := before
+	after.Status = "cancelled"
+
+	// Cancel before refunding, so only the request that actually cancelled it refunds.
+	// Refunds are recorded on their own, so the audit log holds the request for one.
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "reservation.cancel", "reservation", id, before, map[string]interface{}{
+			"reservation": after,
+			"reason":      req.Reason,
+			"refund":      req.Refund,
+		})
+	}
+	cancelled, err := s.reservationRepo.TransitionReservationStatus(ctx, id, reservation.Status, "cancelled", audit)
+	if err != nil {
+		return nil, errors.New("failed to cancel reservation")
+	}
+	if !cancelled {
+		return nil, errors.New("reservation was changed by another request, try again")
+	}
+	expirePendingPayment(ctx, s.paymentRepo, s.midtransService, reservation)
+
+	var refunds []models.Refund
+	if req.Refund && reservation.Status == "confirmed" {
+		shares, err := refundSharesFor(ctx, s.paymentRepo, reservation)
+		if err != nil {
+			return nil, errors.New("reservation was cancelled but its paid payment was not found")
+		}
+
+		for _, share := range shares {
+			var refund *models.Refund
+			if share.payment.PaymentMethod == "cash" {
+				refund, err = s.refundCash(ctx, share.payment, share.amount, req.Reason)
+			} else {
+				refund, err = s.midtransService.RefundPayment(ctx, share.payment, share.amount, req.Reason)
+			}
+			if err != nil {
+				return refunds, fmt.Errorf("reservation was cancelled but refunding the payment failed: %w", err)
+			}
+			refunds = append(refunds, *refund)
+		}
+	}
+
+	// Shares players already paid towards a split booking are always returned
+	if reservation.Status == "pending" {
+		refunds, err = refundPaidShares(ctx, s.paymentRepo, s.midtransService, reservation, req.Reason)
+		if err != nil {
+			return refunds, fmt.Errorf("reservation was cancelled but refunding paid shares failed: %w", err)
+		}
+	}
+	return refunds, nil
+}
+
+// refundCash records cash handed back at the front desk; no gateway is involved.
+func (s *adminReservationService) refundCash(ctx context.Context, payment *models.Payment, amount money.Amount, reason string) (*models.Refund, error) {
+	refund := &models.Refund{
+		PaymentID:     payment.ID,
+		ReservationID: payment.ReservationID,
+		Amount:        amount,
+		Reason:        reason,
+		Status:        "succeeded",
+		RefundKey:     refundKeyFor(payment),
+	}
+	if err := s.refundRepo.CreateRefund(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to save refund: %v", err)
+	}
+
+	payment.Status = "refunded"
+	if _, err := s.paymentRepo.TransitionPaymentStatus(ctx, payment, "paid"); err != nil {
+		return refund, fmt.Errorf("failed to update payment status: %v", err)
+	}
+	return refund, nil
+}
+
+// MoveReservation moves a booking to a new start time, and optionally another date or
+// court, keeping its duration. The customer keeps the price they booked at.
+func (s *adminReservationService) MoveReservation(ctx context.Context, actorID uint, id uint, req *models.MoveReservationRequest) (*models.AdminReservationResponse, error) {
+	reservation, scope, err := s.loadReservation(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != "pending" && reservation.Status != "confirmed" {
+		return nil, errors.New("only pending or confirmed reservations can be moved")
+	}
+	before := toAdminReservationResponse(reservation)
+
+	court, err := s.courtRepo.GetCourtByID(ctx, valueOr(req.CourtID, reservation.CourtID))
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+	if err := checkVenue(scope, court.VenueID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+	start, err := utils.ParseClock(req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	end := start + reservation.DurationMinutes
+	if end > utils.MinutesPerDay {
+		return nil, errors.New("reservation cannot extend past midnight")
+	}
+
+	schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, court, date)
+	if err != nil {
+		return nil, err
+	}
+	if err := schedule.Validate(start, end); err != nil {
+		return nil, err
+	}
+
+	reservation.CourtID = court.ID
+	reservation.Court = *court
+	reservation.ReservationDate = date
+	reservation.TimeSlot = utils.FormatTimeSlot(start, end)
+	reservation.StartAt = slotTime(date, start, courtLocation(court))
+	reservation.EndAt = slotTime(date, end, courtLocation(court))
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "reservation.move", "reservation", id, before, map[string]interface{}{
+			"reservation": toAdminReservationResponse(reservation),
+			"reason":      req.Reason,
+		})
+	}
+	err = s.reservationRepo.MoveReservation(ctx, reservation, audit)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, errors.New("failed to move reservation")
+	}
+
+	moved, err := s.GetReservation(ctx, actorID, id)
+	if err != nil {
+		return nil, err
+	}
+	return moved, nil
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// AuditService lists the audit log of admin changes. Entries are written by the
+// repositories, in the same transaction as the change; see newAuditLog.
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, entityType string, entityID uint, limit int) ([]models.AuditLog, error)
+}
+
+type auditService struct {
+	auditRepo repositories.AuditRepository
+}
+
+func NewAuditService(auditRepo repositories.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, entityType string, entityID uint, limit int) ([]models.AuditLog, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	logs, err := s.auditRepo.GetAuditLogs(ctx, entityType, entityID, limit)
+	if err != nil {
+		return nil, errors.New("failed to get audit logs")
+	}
+	return logs, nil
+}
+
+// newAuditLog builds an audit entry with JSON snapshots of before and after. Repositories
+// save it in the same transaction as the change when it is passed as an AuditEntry.
+func newAuditLog(actorID uint, action, entityType string, entityID uint, before, after interface{}) (*models.AuditLog, error) {
+	log := &models.AuditLog{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+	}
+
+	var err error
+	if log.Before, err = auditSnapshot(before); err != nil {
+		return nil, err
+	}
+	if log.After, err = auditSnapshot(after); err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func auditSnapshot(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit snapshot: %v", err)
+	}
+	return string(data), nil
+}
@@ -44,6 +44,7 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest)
 		Email:    req.Email,
 		Password: hashedPassword,
 		Phone:    req.Phone,
+		Role:     "customer",
 	}
 
 	err = s.userRepo.CreateUser(ctx, user)
@@ -57,6 +58,8 @@ func (s *authService) Register(ctx context.Context, req *models.RegisterRequest)
 		Name:      user.Name,
 		Email:     user.Email,
 		Phone:     user.Phone,
+		Role:      user.Role,
+		VenueID:   user.VenueID,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -76,7 +79,7 @@ func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (stri
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateJWT(user.ID, user.Email)
+	token, err := utils.GenerateJWT(user.ID, user.Email, user.Role)
 	if err != nil {
 		return "", nil, err
 	}
@@ -87,6 +90,8 @@ func (s *authService) Login(ctx context.Context, req *models.LoginRequest) (stri
 		Name:      user.Name,
 		Email:     user.Email,
 		Phone:     user.Phone,
+		Role:      user.Role,
+		VenueID:   user.VenueID,
 		CreatedAt: user.CreatedAt,
 	}
 
@@ -104,6 +109,8 @@ func (s *authService) GetUserProfile(ctx context.Context, userID uint) (*models.
 		Name:      user.Name,
 		Email:     user.Email,
 		Phone:     user.Phone,
+		Role:      user.Role,
+		VenueID:   user.VenueID,
 		CreatedAt: user.CreatedAt,
 	}
 
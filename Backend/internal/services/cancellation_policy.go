@@ -0,0 +1,142 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// refundAmountFor applies the court's cancellation policy to a paid amount:
+// a full refund at least FullRefundHours before start, PartialRefundPercent at least
+// PartialRefundHours before start, and nothing after that.
+func refundAmountFor(court models.Court, paidAmount money.Amount, startAt, now time.Time) money.Amount {
+	hoursBefore := startAt.Sub(now).Hours()
+
+	switch {
+	case hoursBefore >= float64(court.FullRefundHours):
+		return paidAmount
+	case hoursBefore >= float64(court.PartialRefundHours):
+		return paidAmount.Percent(court.PartialRefundPercent)
+	default:
+		return 0
+	}
+}
+
+// refundShare is one settled payment behind a reservation and how much of it can still be
+// refunded for that reservation.
+type refundShare struct {
+	payment *models.Payment
+	amount  money.Amount
+}
+
+// refundSharesFor lists the settled payments covering a confirmed reservation, newest
+// first: the booking payment plus any reschedule top-up. An occurrence of a series shares
+// the series payment, so only its own price is refundable; channel fees and earlier
+// refunds are never refunded again.
+func refundSharesFor(ctx context.Context, paymentRepo repositories.PaymentRepository, reservation *models.Reservation) ([]refundShare, error) {
+	if reservation.SeriesID != nil {
+		payment, err := paymentRepo.GetPaidPaymentBySeriesID(ctx, *reservation.SeriesID)
+		if err != nil {
+			return nil, err
+		}
+		return []refundShare{{payment: payment, amount: reservation.TotalAmount}}, nil
+	}
+
+	payments, err := paymentRepo.GetPaidPaymentsByReservationID(ctx, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(payments) == 0 {
+		return nil, errors.New("no settled payment")
+	}
+	return refundableShares(payments), nil
+}
+
+// refundableShares is what is left to refund of each payment; payments need their Refunds
+// loaded. Refunds still under way count as taken.
+func refundableShares(payments []models.Payment) []refundShare {
+	var shares []refundShare
+	for i := range payments {
+		payment := &payments[i]
+		amount := payment.Amount - payment.Fee
+		for _, refund := range payment.Refunds {
+			if refund.Status == "pending" || refund.Status == "succeeded" {
+				amount -= refund.Amount
+			}
+		}
+		if amount > 0 {
+			shares = append(shares, refundShare{payment: payment, amount: amount})
+		}
+	}
+	return shares
+}
+
+// refundPaidShares returns what players already paid towards a split reservation that was
+// cancelled before it was fully paid. Channel fees are kept, as with any refund.
+func refundPaidShares(ctx context.Context, paymentRepo repositories.PaymentRepository, midtransService MidtransService, reservation *models.Reservation, reason string) ([]models.Refund, error) {
+	if reservation.SplitDeadline == nil {
+		return nil, nil
+	}
+
+	payments, err := paymentRepo.GetPaidPaymentsByReservationID(ctx, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+	shares := refundableShares(payments)
+	return refundShares(ctx, midtransService, shares, sharesTotal(shares), reason)
+}
+
+// sharesTotal is the refundable amount across all shares.
+func sharesTotal(shares []refundShare) money.Amount {
+	var total money.Amount
+	for _, share := range shares {
+		total += share.amount
+	}
+	return total
+}
+
+// refundShares refunds amount through the gateway, taking from the newest payment first.
+// Refunds already issued are returned along with the error when one of them fails.
+func refundShares(ctx context.Context, midtransService MidtransService, shares []refundShare, amount money.Amount, reason string) ([]models.Refund, error) {
+	var refunds []models.Refund
+	for _, share := range shares {
+		if amount <= 0 {
+			break
+		}
+		part := min(amount, share.amount)
+		refund, err := midtransService.RefundPayment(ctx, share.payment, part, reason)
+		if err != nil {
+			return refunds, err
+		}
+		refunds = append(refunds, *refund)
+		amount -= part
+	}
+	return refunds, nil
+}
+
+// expirePendingPayment closes the order still open for a reservation that was just
+// cancelled; reservation holds its status from before. That is its booking payment, or
+// the top-up a reschedule left owing.
+// Failing to do so is only logged; the cancellation stands, and money arriving anyway is
+// refunded when it settles.
+func expirePendingPayment(ctx context.Context, paymentRepo repositories.PaymentRepository, midtransService MidtransService, reservation *models.Reservation) {
+	if reservation.SeriesID != nil {
+		return
+	}
+	purpose := "booking"
+	if reservation.Status != "pending" {
+		purpose = "top_up"
+	}
+
+	payment, err := paymentRepo.GetActivePaymentByPurpose(ctx, reservation.ID, purpose)
+	if err != nil || payment.Status != "pending" {
+		return
+	}
+	if err := midtransService.ExpirePayment(ctx, payment); err != nil {
+		fmt.Printf("ERROR expiring payment %s of cancelled reservation %d: %v\n", payment.MidtransOrderID, reservation.ID, err)
+	}
+}
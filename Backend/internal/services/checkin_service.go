@@ -0,0 +1,181 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCheckInCode is returned for codes that are malformed, forged, or issued before a reschedule.
+	ErrInvalidCheckInCode = errors.New("invalid check-in code")
+	// ErrAlreadyCheckedIn is returned when the booking has already been checked in.
+	ErrAlreadyCheckedIn = errors.New("reservation is already checked in")
+	// ErrCheckInClosed is returned outside the check-in window of a booking.
+	ErrCheckInClosed = errors.New("check-in is not open for this reservation")
+)
+
+// checkInCodePrefix versions the check-in code format.
+const checkInCodePrefix = "CHK1"
+
+// CheckInService issues the signed codes customers show as a QR code at the front desk,
+// checks them in, and settles bookings once their slot has ended. A code is bound to the
+// booking's start time, so rescheduling invalidates codes issued before.
+type CheckInService interface {
+	GetCheckInCode(ctx context.Context, userID uint, reservationID uint) (*models.CheckInCodeResponse, error)
+	CheckIn(ctx context.Context, actorID uint, req *models.CheckInRequest) (*models.AdminReservationResponse, error)
+	CloseFinishedReservations(ctx context.Context) (int64, int64, error)
+}
+
+type checkInService struct {
+	reservationRepo repositories.ReservationRepository
+	userRepo        repositories.UserRepository
+	secret          []byte
+	opensBefore     time.Duration
+}
+
+func NewCheckInService(
+	reservationRepo repositories.ReservationRepository,
+	userRepo repositories.UserRepository,
+	secret string,
+	opensBefore time.Duration,
+) CheckInService {
+	return &checkInService{
+		reservationRepo: reservationRepo,
+		userRepo:        userRepo,
+		secret:          []byte(secret),
+		opensBefore:     opensBefore,
+	}
+}
+
+// GetCheckInCode returns the check-in code of the user's confirmed reservation.
+func (s *checkInService) GetCheckInCode(ctx context.Context, userID uint, reservationID uint) (*models.CheckInCodeResponse, error) {
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.UserID != userID {
+		return nil, ErrNotReservationOwner
+	}
+	if reservation.Status != "confirmed" && reservation.Status != "checked_in" {
+		return nil, errors.New("check-in codes are only issued for confirmed reservations")
+	}
+
+	return &models.CheckInCodeResponse{
+		ReservationID: reservation.ID,
+		Code:          s.sign(reservation.ID, reservation.StartAt),
+		ValidFrom:     reservation.StartAt.Add(-s.opensBefore),
+		ValidUntil:    reservation.EndAt,
+	}, nil
+}
+
+// CheckIn validates a scanned code and marks the booking as checked in. Staff can only
+// check in bookings at their own venue, from opensBefore the start until the slot ends.
+func (s *checkInService) CheckIn(ctx context.Context, actorID uint, req *models.CheckInRequest) (*models.AdminReservationResponse, error) {
+	reservationID, startUnix, err := s.verify(req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if err := checkVenue(scope, reservation.Court.VenueID); err != nil {
+		return nil, err
+	}
+	if reservation.StartAt.Unix() != startUnix {
+		return nil, fmt.Errorf("%w: the reservation was rescheduled, show the current code", ErrInvalidCheckInCode)
+	}
+
+	switch reservation.Status {
+	case "confirmed":
+	case "checked_in":
+		return nil, ErrAlreadyCheckedIn
+	default:
+		return nil, fmt.Errorf("%w: reservation is %s", ErrCheckInClosed, reservation.Status)
+	}
+	if reservation.AmountDue > 0 {
+		return nil, fmt.Errorf("top-up of %s is still due for this reservation", reservation.AmountDue)
+	}
+
+	now := time.Now()
+	opensAt := reservation.StartAt.Add(-s.opensBefore)
+	if now.Before(opensAt) {
+		return nil, fmt.Errorf("%w: check-in opens at %s", ErrCheckInClosed, opensAt.In(courtLocation(&reservation.Court)).Format("2006-01-02 15:04"))
+	}
+	if !now.Before(reservation.EndAt) {
+		return nil, fmt.Errorf("%w: the reservation has ended", ErrCheckInClosed)
+	}
+
+	before := toAdminReservationResponse(reservation)
+	checkedIn := *reservation
+	checkedIn.Status = "checked_in"
+	checkedIn.CheckedInAt = &now
+	checkedIn.CheckedInByID = &actorID
+	after := toAdminReservationResponse(&checkedIn)
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "reservation.check_in", "reservation", reservation.ID, before, after)
+	}
+	applied, err := s.reservationRepo.CheckIn(ctx, reservation.ID, actorID, now, audit)
+	if err != nil {
+		return nil, errors.New("failed to check in reservation")
+	}
+	if !applied {
+		return nil, ErrAlreadyCheckedIn
+	}
+	return &after, nil
+}
+
+// CloseFinishedReservations marks bookings whose slot has ended as completed when they
+// were checked in and as no-shows otherwise. It returns both counts.
+func (s *checkInService) CloseFinishedReservations(ctx context.Context) (int64, int64, error) {
+	return s.reservationRepo.CloseFinishedReservations(ctx, time.Now())
+}
+
+// sign builds the code CHK1.<reservation id>.<start unix>.<signature>.
+func (s *checkInService) sign(reservationID uint, startAt time.Time) string {
+	payload := fmt.Sprintf("%s.%d.%d", checkInCodePrefix, reservationID, startAt.Unix())
+	return payload + "." + s.signature(payload)
+}
+
+func (s *checkInService) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verify checks the signature of a code and returns the reservation and start time it was issued for.
+func (s *checkInService) verify(code string) (uint, int64, error) {
+	parts := strings.Split(strings.TrimSpace(code), ".")
+	if len(parts) != 4 || parts[0] != checkInCodePrefix {
+		return 0, 0, ErrInvalidCheckInCode
+	}
+
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.signature(payload))) {
+		return 0, 0, ErrInvalidCheckInCode
+	}
+
+	reservationID, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, ErrInvalidCheckInCode
+	}
+	startUnix, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCheckInCode
+	}
+	return uint(reservationID), startUnix, nil
+}
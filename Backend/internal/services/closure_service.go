@@ -0,0 +1,156 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrClosureNotFound is returned when the closure to remove does not exist.
+var ErrClosureNotFound = errors.New("closure not found")
+
+// ClosureService manages court and venue closures (maintenance windows, holiday blackouts).
+// Staff manage only the closures of their own venue; closing every venue is for admins.
+type ClosureService interface {
+	CreateClosure(ctx context.Context, actorID uint, req *models.CreateClosureRequest) (*models.Closure, int64, error)
+	DeleteClosure(ctx context.Context, actorID uint, id uint) error
+	GetClosures(ctx context.Context, venueID *uint, from, to *time.Time) ([]models.Closure, error)
+}
+
+type closureService struct {
+	closureRepo repositories.ClosureRepository
+	courtRepo   repositories.CourtRepository
+	venueRepo   repositories.VenueRepository
+	userRepo    repositories.UserRepository
+}
+
+func NewClosureService(
+	closureRepo repositories.ClosureRepository,
+	courtRepo repositories.CourtRepository,
+	venueRepo repositories.VenueRepository,
+	userRepo repositories.UserRepository,
+) ClosureService {
+	return &closureService{
+		closureRepo: closureRepo,
+		courtRepo:   courtRepo,
+		venueRepo:   venueRepo,
+		userRepo:    userRepo,
+	}
+}
+
+// CreateClosure closes a court (or every court) for the range. Active reservations that
+// overlap it are flagged with the closure ID; the number flagged is returned.
+func (s *closureService) CreateClosure(ctx context.Context, actorID uint, req *models.CreateClosureRequest) (*models.Closure, int64, error) {
+	if !req.EndAt.After(req.StartAt) {
+		return nil, 0, errors.New("end_at must be after start_at")
+	}
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A court closure is recorded against the court's venue as well
+	venueID := req.VenueID
+	if req.CourtID != nil {
+		court, err := s.courtRepo.GetCourtByID(ctx, *req.CourtID)
+		if err != nil {
+			return nil, 0, errors.New("court not found")
+		}
+		if venueID != nil && (court.VenueID == nil || *court.VenueID != *venueID) {
+			return nil, 0, errors.New("court does not belong to this venue")
+		}
+		venueID = court.VenueID
+	} else if venueID != nil {
+		if _, err := s.venueRepo.GetVenueByID(ctx, *venueID); err != nil {
+			return nil, 0, ErrVenueNotFound
+		}
+	}
+	if err := checkVenue(scope, venueID); err != nil {
+		return nil, 0, err
+	}
+
+	closure := &models.Closure{
+		CourtID: req.CourtID,
+		VenueID: venueID,
+		StartAt: req.StartAt,
+		EndAt:   req.EndAt,
+		Reason:  req.Reason,
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "closure.create", "closure", closure.ID, nil, closure)
+	}
+	flagged, err := s.closureRepo.CreateClosure(ctx, closure, audit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create closure: %v", err)
+	}
+
+	if flagged > 0 {
+		fmt.Printf("⚠️ Closure %d overlaps %d reservation(s), flagged for rebooking or refund\n", closure.ID, flagged)
+	}
+	return closure, flagged, nil
+}
+
+// DeleteClosure reopens the range and clears the flag on the reservations it affected.
+func (s *closureService) DeleteClosure(ctx context.Context, actorID uint, id uint) error {
+	scope, err := staffVenue(ctx, s.userRepo, actorID)
+	if err != nil {
+		return err
+	}
+
+	closure, err := s.closureRepo.GetClosureByID(ctx, id)
+	if err != nil {
+		return ErrClosureNotFound
+	}
+	if err := checkVenue(scope, closure.VenueID); err != nil {
+		return err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "closure.delete", "closure", id, closure, nil)
+	}
+	err = s.closureRepo.DeleteClosure(ctx, id, audit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrClosureNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete closure: %v", err)
+	}
+	return nil
+}
+
+// GetClosures lists the closures overlapping the calendar dates from through to, taken in
+// the timezone of the venue (or the default venue timezone). By default it covers the
+// next 30 days.
+func (s *closureService) GetClosures(ctx context.Context, venueID *uint, from, to *time.Time) ([]models.Closure, error) {
+	venue := &models.Venue{Timezone: defaultVenueTimezone}
+	if venueID != nil {
+		found, err := s.venueRepo.GetVenueByID(ctx, *venueID)
+		if err != nil {
+			return nil, ErrVenueNotFound
+		}
+		venue = found
+	}
+	loc := venueLocation(venue)
+
+	fromDate := venueToday(loc)
+	if from != nil {
+		fromDate = *from
+	}
+	rangeStart := slotTime(fromDate, 0, loc)
+	rangeEnd := rangeStart.AddDate(0, 0, 30)
+	if to != nil {
+		rangeEnd = slotTime(to.AddDate(0, 0, 1), 0, loc)
+	}
+
+	closures, err := s.closureRepo.GetClosuresBetween(ctx, rangeStart, rangeEnd)
+	if err != nil {
+		return nil, errors.New("failed to get closures")
+	}
+	return closures, nil
+}
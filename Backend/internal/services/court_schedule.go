@@ -0,0 +1,217 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Schedule used for courts without configured operating hours.
+const (
+	defaultOpenTime    = "07:00"
+	defaultCloseTime   = "21:00"
+	defaultSlotMinutes = 60
+)
+
+var (
+	// ErrCourtClosed is returned when booking a court on a day it does not open.
+	ErrCourtClosed = errors.New("court is closed on this date")
+	// ErrOutsideOperatingHours is returned when the range falls outside opening hours.
+	ErrOutsideOperatingHours = errors.New("requested time is outside operating hours")
+	// ErrSlotMisaligned is returned when the range does not follow the court's slot grid.
+	ErrSlotMisaligned = errors.New("requested time does not match the court's slot length")
+)
+
+// daySchedule is when a court can be booked on one date, in minutes since midnight.
+type daySchedule struct {
+	open        int
+	close       int
+	closed      bool
+	slotMinutes int
+	closures    []closedRange
+}
+
+// closedRange is the part of a closure that falls on the schedule's date.
+type closedRange struct {
+	start, end int
+	reason     string
+}
+
+// loadDaySchedule resolves a court's hours on date: special hours for that date first,
+// then the weekly hours for its weekday, then its venue's opening hours (or the default
+// schedule). Closures of the court, its venue or every venue on that date are carved out.
+func loadDaySchedule(
+	ctx context.Context,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	court *models.Court,
+	date time.Time,
+) (*daySchedule, error) {
+	schedule := &daySchedule{slotMinutes: court.SlotMinutes}
+	if schedule.slotMinutes <= 0 {
+		schedule.slotMinutes = defaultSlotMinutes
+	}
+
+	openTime, closeTime := venueHours(court)
+
+	special, err := courtRepo.GetSpecialHours(ctx, court.ID, date)
+	switch {
+	case err == nil:
+		if special.Closed {
+			schedule.closed = true
+			return schedule, nil
+		}
+		openTime, closeTime = special.OpenTime, special.CloseTime
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		hours, err := courtRepo.GetOperatingHours(ctx, court.ID, date.Weekday())
+		switch {
+		case err == nil:
+			if hours.Closed {
+				schedule.closed = true
+				return schedule, nil
+			}
+			openTime, closeTime = hours.OpenTime, hours.CloseTime
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, errors.New("failed to load operating hours")
+		}
+	default:
+		return nil, errors.New("failed to load operating hours")
+	}
+
+	schedule.open, schedule.close, err = utils.ParseTimeSlot(openTime + "-" + closeTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operating hours for court %d: %v", court.ID, err)
+	}
+
+	dayStart := slotTime(date, 0, courtLocation(court))
+	dayEnd := slotTime(date, utils.MinutesPerDay, courtLocation(court))
+	closures, err := closureRepo.GetCourtClosuresBetween(ctx, court.ID, dayStart, dayEnd)
+	if err != nil {
+		return nil, errors.New("failed to load closures")
+	}
+	for _, closure := range closures {
+		schedule.closures = append(schedule.closures, closedRange{
+			start:  minutesInto(dayStart, closure.StartAt),
+			end:    minutesInto(dayStart, closure.EndAt),
+			reason: closure.Reason,
+		})
+	}
+	return schedule, nil
+}
+
+// courtLocation is the timezone of the court's venue, which its opening hours and slot
+// times are given in. Courts without a venue use the default venue timezone.
+func courtLocation(court *models.Court) *time.Location {
+	if court.Venue != nil {
+		return venueLocation(court.Venue)
+	}
+	return venueLocation(&models.Venue{Timezone: defaultVenueTimezone})
+}
+
+// venueLocation loads the venue's timezone. Zones are validated when a venue is saved,
+// so the server's local zone is only used if the zone database lacks it.
+func venueLocation(venue *models.Venue) *time.Location {
+	timezone := venue.Timezone
+	if timezone == "" {
+		timezone = defaultVenueTimezone
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		fmt.Printf("⚠️ Unknown timezone %q of venue %d, using server time: %v\n", timezone, venue.ID, err)
+		return time.Local
+	}
+	return loc
+}
+
+// venueToday is the current date at the venue, as a date like the ones parsed from requests.
+func venueToday(loc *time.Location) time.Time {
+	year, month, day := time.Now().In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// venueHours are the opening hours a court falls back to without weekly hours of its own.
+func venueHours(court *models.Court) (string, string) {
+	if court.Venue != nil && court.Venue.OpenTime != "" && court.Venue.CloseTime != "" {
+		return court.Venue.OpenTime, court.Venue.CloseTime
+	}
+	return defaultOpenTime, defaultCloseTime
+}
+
+// weeklySchedule fills the weekdays without configured hours with the venue's hours,
+// so the result always has seven entries, Sunday first.
+func weeklySchedule(court *models.Court, configured []models.CourtOperatingHours) []models.CourtOperatingHours {
+	openTime, closeTime := venueHours(court)
+
+	week := make([]models.CourtOperatingHours, 7)
+	for weekday := range week {
+		week[weekday] = models.CourtOperatingHours{
+			CourtID:   court.ID,
+			Weekday:   weekday,
+			OpenTime:  openTime,
+			CloseTime: closeTime,
+		}
+	}
+	for _, hours := range configured {
+		if hours.Weekday >= 0 && hours.Weekday < len(week) {
+			week[hours.Weekday] = hours
+		}
+	}
+	return week
+}
+
+// minutesInto converts t to minutes since dayStart, clamped to the day.
+func minutesInto(dayStart, t time.Time) int {
+	minutes := int(t.Sub(dayStart).Minutes())
+	return min(max(minutes, 0), utils.MinutesPerDay)
+}
+
+// closureAt returns the closure overlapping [start, end), if any.
+func (d *daySchedule) closureAt(start, end int) *closedRange {
+	for i := range d.closures {
+		if utils.RangesOverlap(start, end, d.closures[i].start, d.closures[i].end) {
+			return &d.closures[i]
+		}
+	}
+	return nil
+}
+
+// Slots lists the bookable slots of the day, one slot length each, starting at opening time.
+// Slots touched by a closure are left out.
+func (d *daySchedule) Slots() []string {
+	if d.closed {
+		return nil
+	}
+
+	var slots []string
+	for start := d.open; start+d.slotMinutes <= d.close; start += d.slotMinutes {
+		if d.closureAt(start, start+d.slotMinutes) != nil {
+			continue
+		}
+		slots = append(slots, utils.FormatTimeSlot(start, start+d.slotMinutes))
+	}
+	return slots
+}
+
+// Validate checks that [start, end) lies within opening hours, on the slot grid and
+// outside any closure.
+func (d *daySchedule) Validate(start, end int) error {
+	if d.closed {
+		return ErrCourtClosed
+	}
+	if start < d.open || end > d.close {
+		return fmt.Errorf("%w (%s)", ErrOutsideOperatingHours, utils.FormatTimeSlot(d.open, d.close))
+	}
+	if (start-d.open)%d.slotMinutes != 0 || (end-start)%d.slotMinutes != 0 {
+		return fmt.Errorf("%w (%d minutes from %s)", ErrSlotMisaligned, d.slotMinutes, utils.FormatClock(d.open))
+	}
+	if closure := d.closureAt(start, end); closure != nil {
+		return fmt.Errorf("%w: %s", ErrCourtClosed, closure.reason)
+	}
+	return nil
+}
@@ -3,23 +3,37 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/pkg/utils"
 	"context"
+	"errors"
 	"time"
 )
 
 type CourtService interface {
 	GetAllCourts(ctx context.Context) ([]models.CourtResponse, error)
 	GetAvailableCourts(ctx context.Context, date string) ([]models.AvailableSlotResponse, error)
+	GetVenueCourts(ctx context.Context, venueID uint) ([]models.CourtResponse, error)
+	GetVenueAvailableCourts(ctx context.Context, venueID uint, date string) ([]models.AvailableSlotResponse, error)
 	CheckTimeSlotAvailability(ctx context.Context, req models.CheckAvailabilityRequest) (bool, error)
-	GetCourtByID(ctx context.Context, id uint) (*models.CourtResponse, error)
+	GetCourtByID(ctx context.Context, id uint) (*models.CourtDetailResponse, error)
 }
 
 type courtService struct {
-	courtRepo repositories.CourtRepository
+	courtRepo      repositories.CourtRepository
+	closureRepo    repositories.ClosureRepository
+	pricingService PricingService
 }
 
-func NewCourtService(courtRepo repositories.CourtRepository) CourtService {
-	return &courtService{courtRepo: courtRepo}
+func NewCourtService(
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	pricingService PricingService,
+) CourtService {
+	return &courtService{
+		courtRepo:      courtRepo,
+		closureRepo:    closureRepo,
+		pricingService: pricingService,
+	}
 }
 
 func (s *courtService) GetAllCourts(ctx context.Context) ([]models.CourtResponse, error) {
@@ -29,19 +43,41 @@ func (s *courtService) GetAllCourts(ctx context.Context) ([]models.CourtResponse
 	}
 
 	var courtResponses []models.CourtResponse
-	for _, court := range courts {
-		courtResponses = append(courtResponses, models.CourtResponse{
-			ID:           court.ID,
-			Name:         court.Name,
-			Location:     court.Location,     // NEW
-			PricePerHour: court.PricePerHour, // NEW
-			Status:       court.Status,
-		})
+	for i := range courts {
+		courtResponses = append(courtResponses, toCourtResponse(&courts[i]))
 	}
 
 	return courtResponses, nil
 }
 
+func toCourtResponse(court *models.Court) models.CourtResponse {
+	photos := court.Photos
+	if photos == nil {
+		photos = []string{}
+	}
+
+	response := models.CourtResponse{
+		ID:           court.ID,
+		VenueID:      court.VenueID,
+		Name:         court.Name,
+		Status:       court.Status,
+		Location:     court.Location,
+		PricePerHour: court.PricePerHour,
+		SlotMinutes:  court.SlotMinutes,
+		Description:  court.Description,
+		Environment:  court.Environment,
+		FloorType:    court.FloorType,
+		Lighting:     court.Lighting,
+		HasAC:        court.HasAC,
+		Capacity:     court.Capacity,
+		Photos:       photos,
+	}
+	if court.Venue != nil {
+		response.VenueName = court.Venue.Name
+	}
+	return response
+}
+
 func (s *courtService) GetAvailableCourts(ctx context.Context, date string) ([]models.AvailableSlotResponse, error) {
 	// Parse date
 	parsedDate, err := time.Parse("2006-01-02", date)
@@ -55,11 +91,59 @@ func (s *courtService) GetAvailableCourts(ctx context.Context, date string) ([]m
 		return nil, err
 	}
 
+	return s.availableCourts(ctx, courts, parsedDate)
+}
+
+func (s *courtService) GetVenueCourts(ctx context.Context, venueID uint) ([]models.CourtResponse, error) {
+	courts, err := s.courtRepo.GetVenueCourts(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	courtResponses := make([]models.CourtResponse, 0, len(courts))
+	for i := range courts {
+		courtResponses = append(courtResponses, toCourtResponse(&courts[i]))
+	}
+	return courtResponses, nil
+}
+
+// GetVenueAvailableCourts is GetAvailableCourts limited to one venue's courts.
+func (s *courtService) GetVenueAvailableCourts(ctx context.Context, venueID uint, date string) ([]models.AvailableSlotResponse, error) {
+	parsedDate, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, err
+	}
+
+	courts, err := s.courtRepo.GetVenueCourts(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.availableCourts(ctx, courts, parsedDate)
+}
+
+// availableCourts lists the free slots of each court on date, skipping fully booked courts.
+func (s *courtService) availableCourts(ctx context.Context, courts []models.Court, parsedDate time.Time) ([]models.AvailableSlotResponse, error) {
+	date := parsedDate.Format("2006-01-02")
 	var availableCourts []models.AvailableSlotResponse
 
-	for _, court := range courts {
+	for i := range courts {
+		court := &courts[i]
+
+		// Generate the day's slots from the court's schedule
+		schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, court, parsedDate)
+		if err != nil {
+			return nil, err
+		}
+
 		// Get available time slots for this court
-		availableSlots, err := s.courtRepo.GetAvailableTimeSlots(ctx, parsedDate, court.ID)
+		availableSlots, err := s.courtRepo.GetAvailableTimeSlots(ctx, parsedDate, court.ID, schedule.Slots())
+		if err != nil {
+			return nil, err
+		}
+
+		// Rates for the day (peak hours, weekends, holidays)
+		rates, err := s.pricingService.DayRates(ctx, court, parsedDate)
 		if err != nil {
 			return nil, err
 		}
@@ -67,9 +151,19 @@ func (s *courtService) GetAvailableCourts(ctx context.Context, date string) ([]m
 		// Convert to TimeSlot models
 		var timeSlots []models.TimeSlot
 		for _, slot := range availableSlots {
+			start, end, err := utils.ParseTimeSlot(slot)
+			if err != nil {
+				return nil, err
+			}
+			quote, err := rates.Quote(start, end)
+			if err != nil {
+				return nil, err
+			}
+
 			timeSlots = append(timeSlots, models.TimeSlot{
 				Time:     slot,
 				IsBooked: false,
+				Price:    quote.Total,
 			})
 		}
 
@@ -98,6 +192,25 @@ func (s *courtService) CheckTimeSlotAvailability(ctx context.Context, req models
 		return false, err
 	}
 
+	court, err := s.courtRepo.GetCourtByID(ctx, req.CourtID)
+	if err != nil {
+		return false, err
+	}
+
+	start, end, err := utils.ParseTimeSlot(req.TimeSlot)
+	if err != nil {
+		return false, err
+	}
+
+	// Outside opening hours or off the slot grid is never available
+	schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, court, parsedDate)
+	if err != nil {
+		return false, err
+	}
+	if schedule.Validate(start, end) != nil {
+		return false, nil
+	}
+
 	// Check if the time slot is available for the court
 	isAvailable, err := s.courtRepo.CheckCourtAvailability(ctx, parsedDate, req.TimeSlot, req.CourtID)
 	if err != nil {
@@ -107,17 +220,85 @@ func (s *courtService) CheckTimeSlotAvailability(ctx context.Context, req models
 	return isAvailable, nil
 }
 
-func (s *courtService) GetCourtByID(ctx context.Context, id uint) (*models.CourtResponse, error) {
+// GetCourtByID returns the full court page, including today's slots with their prices.
+func (s *courtService) GetCourtByID(ctx context.Context, id uint) (*models.CourtDetailResponse, error) {
 	court, err := s.courtRepo.GetCourtByID(ctx, id)
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+
+	today := venueToday(courtLocation(court))
+
+	rules, err := s.pricingService.CurrentRules(ctx, court.ID, today)
 	if err != nil {
 		return nil, err
 	}
 
-	courtResponse := &models.CourtResponse{
-		ID:     court.ID,
-		Name:   court.Name,
-		Status: court.Status,
+	hours, err := s.courtRepo.GetWeeklyHours(ctx, court.ID)
+	if err != nil {
+		return nil, errors.New("failed to load operating hours")
+	}
+
+	todaySlots, err := s.daySlots(ctx, court, today)
+	if err != nil {
+		return nil, err
 	}
 
-	return courtResponse, nil
+	return &models.CourtDetailResponse{
+		CourtResponse:         toCourtResponse(court),
+		FullRefundHours:       court.FullRefundHours,
+		PartialRefundHours:    court.PartialRefundHours,
+		PartialRefundPercent:  court.PartialRefundPercent,
+		RescheduleCutoffHours: court.RescheduleCutoffHours,
+		PricingRules:          rules,
+		OperatingHours:        weeklySchedule(court, hours),
+		Today: models.AvailableSlotResponse{
+			CourtID:   court.ID,
+			CourtName: court.Name,
+			Date:      today.Format("2006-01-02"),
+			TimeSlots: todaySlots,
+		},
+	}, nil
+}
+
+// daySlots lists every bookable slot of the court on date, booked or not, with its price.
+func (s *courtService) daySlots(ctx context.Context, court *models.Court, date time.Time) ([]models.TimeSlot, error) {
+	schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, court, date)
+	if err != nil {
+		return nil, err
+	}
+	slots := schedule.Slots()
+
+	availableSlots, err := s.courtRepo.GetAvailableTimeSlots(ctx, date, court.ID, slots)
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]bool, len(availableSlots))
+	for _, slot := range availableSlots {
+		available[slot] = true
+	}
+
+	rates, err := s.pricingService.DayRates(ctx, court, date)
+	if err != nil {
+		return nil, err
+	}
+
+	timeSlots := make([]models.TimeSlot, 0, len(slots))
+	for _, slot := range slots {
+		start, end, err := utils.ParseTimeSlot(slot)
+		if err != nil {
+			return nil, err
+		}
+		quote, err := rates.Quote(start, end)
+		if err != nil {
+			return nil, err
+		}
+
+		timeSlots = append(timeSlots, models.TimeSlot{
+			Time:     slot,
+			IsBooked: !available[slot],
+			Price:    quote.Total,
+		})
+	}
+	return timeSlots, nil
 }
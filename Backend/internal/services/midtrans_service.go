@@ -0,0 +1,817 @@
+package services
+
+import (
+	"backend/internal/gateway"
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned when a notification's signature_key does not match.
+	ErrInvalidSignature = gateway.ErrInvalidSignature
+	// ErrAmountMismatch is returned when a notification's gross_amount differs from the stored payment.
+	ErrAmountMismatch = errors.New("notification amount does not match payment")
+	// ErrPaymentNotFound is returned when a notification references an unknown order.
+	ErrPaymentNotFound = errors.New("payment not found")
+	// ErrEmptyNotification is returned for an empty webhook body outside test mode.
+	ErrEmptyNotification = errors.New("empty notification body")
+	// ErrInvalidNotification is returned when the webhook body is not a valid notification.
+	ErrInvalidNotification = gateway.ErrInvalidNotification
+	// ErrRefundFailed is returned when Midtrans rejects a refund request.
+	ErrRefundFailed = errors.New("refund failed")
+)
+
+type MidtransService interface {
+	CreatePayment(ctx context.Context, reservation *models.Reservation, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error)
+	CreateSeriesPayment(ctx context.Context, series *models.ReservationSeries, occurrences []models.Reservation, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error)
+	CreateSharePayment(ctx context.Context, reservation *models.Reservation, participant *models.ReservationParticipant, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error)
+	CreateRemainderPayment(ctx context.Context, reservation *models.Reservation, remaining money.Amount, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error)
+	HandleNotification(ctx context.Context, body []byte, headers map[string][]string) (*models.PaymentNotification, error)
+	RefreshPaymentStatus(ctx context.Context, paymentID uint, userID uint) (*models.Payment, error)
+	ReconcilePendingPayments(ctx context.Context, createdBefore time.Time) (int, error)
+	ExpirePayment(ctx context.Context, payment *models.Payment) error
+	RefundPayment(ctx context.Context, payment *models.Payment, amount money.Amount, reason string) (*models.Refund, error)
+	RetryPendingRefunds(ctx context.Context, updatedBefore time.Time) (int, error)
+}
+
+// maxRefundAttempts is how often a refund is sent to the gateway before it is marked failed
+// and left for staff to settle by hand.
+const maxRefundAttempts = 10
+
+type PaymentResponse struct {
+	SnapToken   string       `json:"snap_token,omitempty"`
+	VaNumber    string       `json:"va_number,omitempty"`
+	VaBank      string       `json:"va_bank,omitempty"`
+	BillerCode  string       `json:"biller_code,omitempty"`
+	RedirectURL string       `json:"redirect_url,omitempty"`
+	OrderID     string       `json:"order_id"`
+	Amount      money.Amount `json:"amount"`
+	Fee         money.Amount `json:"fee"`
+	Status      string       `json:"status"`
+}
+
+type midtransService struct {
+	gateway         gateway.PaymentGateway
+	paymentRepo     repositories.PaymentRepository
+	participantRepo repositories.ParticipantRepository
+
+	notificationRepo       repositories.PaymentNotificationRepository
+	refundRepo             repositories.RefundRepository
+	allowTestNotifications bool
+}
+
+// NewMidtransService processes payments through paymentGateway: Midtrans in production,
+// or the fake gateway for offline development.
+func NewMidtransService(
+	paymentGateway gateway.PaymentGateway,
+	paymentRepo repositories.PaymentRepository,
+	participantRepo repositories.ParticipantRepository,
+	notificationRepo repositories.PaymentNotificationRepository,
+	refundRepo repositories.RefundRepository,
+	allowTestNotifications bool,
+) MidtransService {
+	return &midtransService{
+		gateway:         paymentGateway,
+		paymentRepo:     paymentRepo,
+		participantRepo: participantRepo,
+
+		notificationRepo:       notificationRepo,
+		refundRepo:             refundRepo,
+		allowTestNotifications: allowTestNotifications,
+	}
+}
+
+func (s *midtransService) CreatePayment(ctx context.Context, reservation *models.Reservation, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error) {
+	// Unique per attempt, so a new payment can be started after an expired or failed one
+	payment := &models.Payment{
+		ReservationID:   reservation.ID,
+		MidtransOrderID: fmt.Sprintf("ORDER-%d-%d", reservation.ID, time.Now().Unix()),
+	}
+	item := gateway.Item{
+		ID:    fmt.Sprintf("COURT-%d", reservation.CourtID),
+		Price: reservation.TotalAmount,
+		Qty:   1,
+		Name:  fmt.Sprintf("Court Booking - %s", reservation.TimeSlot),
+	}
+
+	payableUntil := reservation.HoldExpiresAt
+
+	// A confirmed booking moved to a pricier slot only pays the difference
+	if reservation.Status == "confirmed" && reservation.AmountDue > 0 {
+		payment.Purpose = "top_up"
+		payment.MidtransOrderID = fmt.Sprintf("TOPUP-%d-%d", reservation.ID, time.Now().Unix())
+		item.Price = reservation.AmountDue
+		item.Name = fmt.Sprintf("Reschedule Top-up - %s", reservation.TimeSlot)
+		payableUntil = nil
+	}
+
+	fmt.Printf("🎯 Creating payment for reservation %d\n", reservation.ID)
+	return s.charge(ctx, payment, item, user, channel, payableUntil)
+}
+
+// CreateSeriesPayment charges all pending occurrences of a series as one order. The
+// payment is attached to the first occurrence and confirms the whole series when paid.
+func (s *midtransService) CreateSeriesPayment(ctx context.Context, series *models.ReservationSeries, occurrences []models.Reservation, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error) {
+	if len(occurrences) == 0 {
+		return nil, errors.New("series has no occurrences to pay for")
+	}
+
+	var bookingAmount money.Amount
+	var payableUntil *time.Time
+	for i := range occurrences {
+		occurrence := &occurrences[i]
+		bookingAmount += occurrence.TotalAmount
+		if occurrence.HoldExpiresAt != nil && (payableUntil == nil || occurrence.HoldExpiresAt.Before(*payableUntil)) {
+			payableUntil = occurrence.HoldExpiresAt
+		}
+	}
+
+	payment := &models.Payment{
+		ReservationID:   occurrences[0].ID,
+		SeriesID:        &series.ID,
+		MidtransOrderID: fmt.Sprintf("SERIES-%d-%d", series.ID, time.Now().Unix()),
+	}
+	item := gateway.Item{
+		ID:    fmt.Sprintf("SERIES-%d", series.ID),
+		Price: bookingAmount,
+		Qty:   1,
+		Name:  fmt.Sprintf("Court Booking - %d sessions, %s-%s", len(occurrences), series.StartTime, series.EndTime),
+	}
+
+	fmt.Printf("🎯 Creating payment for series %d (%d occurrences)\n", series.ID, len(occurrences))
+	return s.charge(ctx, payment, item, user, channel, payableUntil)
+}
+
+// CreateSharePayment charges one player's share of a split reservation as its own order.
+func (s *midtransService) CreateSharePayment(ctx context.Context, reservation *models.Reservation, participant *models.ReservationParticipant, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error) {
+	payment := &models.Payment{
+		ReservationID:   reservation.ID,
+		ParticipantID:   &participant.ID,
+		Purpose:         "share",
+		MidtransOrderID: fmt.Sprintf("SHARE-%d-%d", participant.ID, time.Now().Unix()),
+	}
+	item := gateway.Item{
+		ID:    fmt.Sprintf("COURT-%d", reservation.CourtID),
+		Price: participant.ShareAmount,
+		Qty:   1,
+		Name:  fmt.Sprintf("Court Booking Share - %s", reservation.TimeSlot),
+	}
+
+	fmt.Printf("🎯 Creating share payment for reservation %d (participant %d)\n", reservation.ID, participant.ID)
+	return s.charge(ctx, payment, item, user, channel, reservation.HoldExpiresAt)
+}
+
+// CreateRemainderPayment charges the organiser for the shares still unpaid after the split deadline.
+func (s *midtransService) CreateRemainderPayment(ctx context.Context, reservation *models.Reservation, remaining money.Amount, user *models.User, channel *models.PaymentChannel) (*PaymentResponse, error) {
+	payment := &models.Payment{
+		ReservationID:   reservation.ID,
+		Purpose:         "remainder",
+		MidtransOrderID: fmt.Sprintf("REMAIN-%d-%d", reservation.ID, time.Now().Unix()),
+	}
+	item := gateway.Item{
+		ID:    fmt.Sprintf("COURT-%d", reservation.CourtID),
+		Price: remaining,
+		Qty:   1,
+		Name:  fmt.Sprintf("Court Booking Unpaid Shares - %s", reservation.TimeSlot),
+	}
+
+	fmt.Printf("🎯 Creating remainder payment for reservation %d\n", reservation.ID)
+	return s.charge(ctx, payment, item, user, channel, reservation.HoldExpiresAt)
+}
+
+// charge adds the channel fee to the booking item, charges it through the gateway and
+// saves payment with the result. An order for a held slot expires with the hold, so it
+// cannot be paid after the slot was released.
+func (s *midtransService) charge(ctx context.Context, payment *models.Payment, booking gateway.Item, user *models.User, channel *models.PaymentChannel, payableUntil *time.Time) (*PaymentResponse, error) {
+	fee := channelFee(channel, booking.Price)
+	amount := booking.Price + fee
+
+	fmt.Printf("🎯 OrderID: %s, channel: %s, amount: %d (fee %d)\n",
+		payment.MidtransOrderID, channel.Code, amount, fee)
+
+	// Midtrans requires item prices to add up to gross_amount
+	items := []gateway.Item{booking}
+	if fee > 0 {
+		items = append(items, gateway.Item{
+			ID:    "FEE-" + channel.Code,
+			Price: fee,
+			Qty:   1,
+			Name:  channel.Name + " fee",
+		})
+	}
+
+	// The order must expire with the hold, and Midtrans counts expiry in whole minutes
+	var expiry time.Duration
+	if payableUntil != nil {
+		expiry = time.Until(*payableUntil)
+		if expiry <= 0 {
+			return nil, fmt.Errorf("%w: reservation hold has expired", ErrReservationNotPayable)
+		}
+		if expiry < gateway.MinExpiry {
+			return nil, fmt.Errorf("%w: less than a minute of the reservation hold is left", ErrReservationNotPayable)
+		}
+	}
+
+	result, err := s.gateway.Charge(ctx, gateway.ChargeRequest{
+		OrderID:       payment.MidtransOrderID,
+		Amount:        amount,
+		PaymentMethod: channel.PaymentMethod,
+		Bank:          channel.Bank,
+		Customer: gateway.Customer{
+			Name:  user.Name,
+			Email: user.Email,
+			Phone: user.Phone,
+		},
+		Items:  items,
+		Expiry: expiry,
+	})
+	if errors.Is(err, gateway.ErrExpiryTooShort) {
+		return nil, fmt.Errorf("%w: less than a minute of the reservation hold is left", ErrReservationNotPayable)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to database
+	payment.Amount = amount
+	payment.Fee = fee
+	payment.Currency = money.Currency
+	payment.Status = "pending"
+	payment.PaymentMethod = channel.PaymentMethod
+	payment.VaNumber = result.VaNumber
+	payment.VaBank = result.VaBank
+	payment.BillerCode = result.BillerCode
+	payment.SnapToken = result.SnapToken
+	payment.RedirectURL = result.RedirectURL
+	if expiry > 0 {
+		// Counted from now, so the order has lapsed at Midtrans by the time this passes
+		expiresAt := time.Now().Add(expiry)
+		payment.ExpiresAt = &expiresAt
+	}
+
+	err = s.paymentRepo.CreatePayment(ctx, payment)
+	if errors.Is(err, ErrActivePaymentExists) {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Printf("❌ ERROR saving payment to database: %v\n", err)
+		return nil, fmt.Errorf("failed to save payment: %v", err)
+	}
+
+	fmt.Printf("✅ Payment saved to database with ID: %d\n", payment.ID)
+
+	return paymentResponseFrom(payment), nil
+}
+
+// HandleNotification processes a Midtrans webhook body and records it, together with
+// the verification result and outcome, in the payment_notifications log.
+func (s *midtransService) HandleNotification(ctx context.Context, body []byte, headers map[string][]string) (*models.PaymentNotification, error) {
+	fmt.Printf("=== MIDTRANS NOTIFICATION RECEIVED ===\n")
+	fmt.Printf("Raw body: %s\n", string(body))
+
+	headerJSON, _ := json.Marshal(headers)
+	record := &models.PaymentNotification{
+		RawBody: string(body),
+		Headers: string(headerJSON),
+	}
+
+	err := s.processNotification(ctx, body, record)
+	switch {
+	case err == nil:
+		// Outcome already set by processNotification
+	case errors.Is(err, ErrEmptyNotification),
+		errors.Is(err, ErrInvalidNotification),
+		errors.Is(err, ErrInvalidSignature),
+		errors.Is(err, ErrAmountMismatch),
+		errors.Is(err, ErrPaymentNotFound):
+		record.Outcome = "rejected"
+	default:
+		record.Outcome = "failed"
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if logErr := s.notificationRepo.CreateNotification(ctx, record); logErr != nil {
+		fmt.Printf("ERROR saving notification log: %v\n", logErr)
+	}
+
+	fmt.Printf("=== NOTIFICATION PROCESSING COMPLETE (%s) ===\n", record.Outcome)
+	return record, err
+}
+
+func (s *midtransService) processNotification(ctx context.Context, body []byte, record *models.PaymentNotification) error {
+	// Midtrans dashboard test notifications are only acknowledged when explicitly enabled
+	if len(body) == 0 {
+		if s.allowTestNotifications {
+			record.Outcome = "test"
+			return nil
+		}
+		return ErrEmptyNotification
+	}
+
+	notif, err := s.gateway.VerifyNotification(body)
+	if notif == nil {
+		fmt.Printf("ERROR parsing notification: %v\n", err)
+		return err
+	}
+	record.OrderID = notif.OrderID
+	record.TransactionStatus = notif.TransactionStatus
+
+	fmt.Printf("Parsed notification - OrderID: %s, Status: %s\n", notif.OrderID, notif.TransactionStatus)
+
+	if s.allowTestNotifications && strings.HasPrefix(notif.OrderID, "payment_notif_test") {
+		fmt.Printf("INFO: Midtrans test notification - OrderID: %s\n", notif.OrderID)
+		record.Outcome = "test"
+		return nil
+	}
+
+	// Verify the notification really comes from the gateway
+	signatureValid := err == nil
+	record.SignatureValid = &signatureValid
+	if !signatureValid {
+		fmt.Printf("ERROR invalid signature for OrderID: %s\n", notif.OrderID)
+		return err
+	}
+
+	// Verify the amount matches what we charged
+	payment, err := s.paymentRepo.GetPaymentByOrderID(ctx, notif.OrderID)
+	if err != nil {
+		fmt.Printf("ERROR payment not found for OrderID: %s\n", notif.OrderID)
+		return ErrPaymentNotFound
+	}
+	if !amountMatches(notif.GrossAmount, payment.Amount) {
+		fmt.Printf("ERROR amount mismatch for OrderID: %s (got %s, expected %d)\n",
+			notif.OrderID, notif.GrossAmount, payment.Amount)
+		return ErrAmountMismatch
+	}
+
+	if err := s.applyTransactionStatus(ctx, payment, *notif); err != nil {
+		return err
+	}
+
+	record.Outcome = "processed"
+	return nil
+}
+
+// applyTransactionStatus moves the payment through the payment state machine and runs the
+// side effects of a transition exactly once. Duplicate and out-of-order updates are ignored.
+func (s *midtransService) applyTransactionStatus(ctx context.Context, payment *models.Payment, update gateway.Transaction) error {
+	if update.TransactionID != "" &&
+		payment.TransactionID == update.TransactionID &&
+		payment.TransactionStatus == update.TransactionStatus {
+		fmt.Printf("INFO: duplicate %s update for transaction %s, skipping\n", update.TransactionStatus, update.TransactionID)
+		return nil
+	}
+
+	newStatus, ok := paymentStatusFor(update.TransactionStatus, update.FraudStatus)
+	if !ok {
+		fmt.Printf("INFO: ignoring unknown transaction status %q for OrderID: %s\n", update.TransactionStatus, payment.MidtransOrderID)
+		return nil
+	}
+
+	fromStatus := payment.Status
+	if newStatus != fromStatus && !canTransitionPayment(fromStatus, newStatus) {
+		fmt.Printf("INFO: ignoring transition %s -> %s for OrderID: %s\n", fromStatus, newStatus, payment.MidtransOrderID)
+		return nil
+	}
+
+	// Update payment (only if nobody changed it in the meantime)
+	payment.Status = newStatus
+	payment.TransactionID = update.TransactionID
+	payment.TransactionStatus = update.TransactionStatus
+	if newStatus == "paid" && payment.PaymentTime.IsZero() {
+		payment.PaymentTime = parseMidtransTime(update.SettlementTime)
+	}
+
+	fmt.Printf("Updating payment for OrderID: %s from %s to %s\n", payment.MidtransOrderID, fromStatus, newStatus)
+
+	// The order was closed before the money arrived, and another payment may have replaced it
+	if newStatus == "paid" && fromStatus == "expired" {
+		return s.settleUnneededPayment(ctx, payment, fromStatus, "Payment arrived after its order was closed")
+	}
+
+	// A booking payment confirms its reservation in the same transaction
+	if newStatus == "paid" && fromStatus != "paid" && payment.SeriesID == nil && payment.Purpose == "booking" {
+		return s.settleReservationPayment(ctx, payment, fromStatus)
+	}
+
+	// A series payment confirms every occurrence it paid for in the same transaction
+	if newStatus == "paid" && fromStatus != "paid" && payment.SeriesID != nil {
+		return s.settleSeriesPayment(ctx, payment, fromStatus)
+	}
+
+	// A top-up settles the difference left by a reschedule; the booking is already confirmed
+	if newStatus == "paid" && fromStatus != "paid" && payment.Purpose == "top_up" {
+		return s.settleTopUpPayment(ctx, payment, fromStatus)
+	}
+
+	applied, err := s.paymentRepo.TransitionPaymentStatus(ctx, payment, fromStatus)
+	if err != nil {
+		fmt.Printf("ERROR updating payment status: %v\n", err)
+		return fmt.Errorf("failed to update payment status: %v", err)
+	}
+	if !applied {
+		fmt.Printf("INFO: payment %s changed concurrently, skipping\n", payment.MidtransOrderID)
+		return nil
+	}
+	if newStatus == fromStatus {
+		return nil
+	}
+
+	// Shares of a split reservation confirm it once all of them are paid or covered
+	if newStatus == "paid" && (payment.Purpose == "share" || payment.Purpose == "remainder") {
+		return s.applySplitPayment(ctx, payment)
+	}
+
+	return nil
+}
+
+// settleReservationPayment marks a booking payment as paid and confirms its reservation.
+// A reservation that was cancelled or whose hold expired before the money arrived may
+// already be booked by someone else, so it stays as it is and the payment is refunded. The
+// refund is saved with the payment, so one the gateway rejects is retried later.
+func (s *midtransService) settleReservationPayment(ctx context.Context, payment *models.Payment, fromStatus string) error {
+	fmt.Printf("Payment is PAID, updating reservation %d...\n", payment.ReservationID)
+	lateRefund := newRefund(payment, payment.Amount-payment.Fee, "Reservation was no longer held when the payment arrived")
+	applied, confirmed, err := s.paymentRepo.SettleReservationPayment(ctx, payment, fromStatus, lateRefund)
+	if err != nil {
+		fmt.Printf("ERROR settling payment: %v\n", err)
+		return fmt.Errorf("failed to update payment status: %v", err)
+	}
+	if !applied {
+		fmt.Printf("INFO: payment %s changed concurrently, skipping\n", payment.MidtransOrderID)
+		return nil
+	}
+	if confirmed {
+		fmt.Printf("Reservation %d updated to 'confirmed'\n", payment.ReservationID)
+		return nil
+	}
+
+	fmt.Printf("⚠️ Reservation %d is no longer pending, refunding OrderID %s\n", payment.ReservationID, payment.MidtransOrderID)
+	if err := s.submitRefund(ctx, payment, lateRefund); err != nil {
+		fmt.Printf("❌ ERROR refunding late payment OrderID %s, will retry: %v\n", payment.MidtransOrderID, err)
+	}
+	return nil
+}
+
+// settleTopUpPayment marks a reschedule top-up as paid and clears the amount its booking
+// owes. A booking cancelled before the money arrived no longer owes it, so the top-up is
+// refunded, saved with the payment and retried if the gateway rejects it.
+func (s *midtransService) settleTopUpPayment(ctx context.Context, payment *models.Payment, fromStatus string) error {
+	fmt.Printf("Top-up is PAID, clearing amount due on reservation %d...\n", payment.ReservationID)
+	lateRefund := newRefund(payment, payment.Amount-payment.Fee, "Reservation no longer owed the top-up when the payment arrived")
+	applied, cleared, err := s.paymentRepo.SettleTopUpPayment(ctx, payment, fromStatus, lateRefund)
+	if err != nil {
+		fmt.Printf("ERROR settling top-up: %v\n", err)
+		return fmt.Errorf("failed to clear amount due: %v", err)
+	}
+	if !applied {
+		fmt.Printf("INFO: payment %s changed concurrently, skipping\n", payment.MidtransOrderID)
+		return nil
+	}
+	if cleared {
+		return nil
+	}
+
+	fmt.Printf("⚠️ Reservation %d no longer owes a top-up, refunding OrderID %s\n", payment.ReservationID, payment.MidtransOrderID)
+	if err := s.submitRefund(ctx, payment, lateRefund); err != nil {
+		fmt.Printf("❌ ERROR refunding late payment OrderID %s, will retry: %v\n", payment.MidtransOrderID, err)
+	}
+	return nil
+}
+
+// settleSeriesPayment marks a series payment as paid and confirms the occurrences still
+// held. Occurrences cancelled or expired before the money arrived are not booked again;
+// their price, or the whole amount when none is left, is refunded. As with single
+// bookings, the refund is saved with the payment and retried if the gateway rejects it.
+func (s *midtransService) settleSeriesPayment(ctx context.Context, payment *models.Payment, fromStatus string) error {
+	fmt.Printf("Payment is PAID, confirming series %d...\n", *payment.SeriesID)
+	lateRefund := newRefund(payment, payment.Amount-payment.Fee, "Series occurrences were no longer held when the payment arrived")
+	applied, confirmed, err := s.paymentRepo.SettleSeriesPayment(ctx, payment, fromStatus, lateRefund)
+	if err != nil {
+		fmt.Printf("ERROR settling series payment: %v\n", err)
+		return fmt.Errorf("failed to update series status: %v", err)
+	}
+	if !applied {
+		fmt.Printf("INFO: payment %s changed concurrently, skipping\n", payment.MidtransOrderID)
+		return nil
+	}
+	fmt.Printf("Series %d: %d occurrence(s) updated to 'confirmed'\n", *payment.SeriesID, len(confirmed))
+	if lateRefund.Amount <= 0 {
+		return nil
+	}
+
+	fmt.Printf("⚠️ Series %d was not fully held, refunding %d of OrderID %s\n", *payment.SeriesID, lateRefund.Amount, payment.MidtransOrderID)
+	if err := s.submitRefund(ctx, payment, lateRefund); err != nil {
+		fmt.Printf("❌ ERROR refunding late payment OrderID %s, will retry: %v\n", payment.MidtransOrderID, err)
+	}
+	return nil
+}
+
+// settleUnneededPayment marks a payment as paid and refunds all of it, for money nothing is
+// waiting for any more. The refund is saved with the payment, so one the gateway rejects
+// is retried later.
+func (s *midtransService) settleUnneededPayment(ctx context.Context, payment *models.Payment, fromStatus string, reason string) error {
+	refund := newRefund(payment, payment.Amount-payment.Fee, reason)
+	applied, err := s.paymentRepo.SettleUnneededPayment(ctx, payment, fromStatus, refund)
+	if err != nil {
+		fmt.Printf("ERROR settling payment: %v\n", err)
+		return fmt.Errorf("failed to update payment status: %v", err)
+	}
+	if !applied {
+		fmt.Printf("INFO: payment %s changed concurrently, skipping\n", payment.MidtransOrderID)
+		return nil
+	}
+
+	fmt.Printf("⚠️ Payment %s is no longer needed, refunding it\n", payment.MidtransOrderID)
+	if err := s.submitRefund(ctx, payment, refund); err != nil {
+		fmt.Printf("❌ ERROR refunding OrderID %s, will retry: %v\n", payment.MidtransOrderID, err)
+	}
+	return nil
+}
+
+// ExpirePayment closes a pending payment so it can no longer be paid. The gateway is asked
+// first, so a settlement it already has is applied rather than lost, and an order still
+// open there is expired. A payment the gateway has no transaction for yet (a Snap order
+// the customer never opened) is expired here; should it be paid after all, the money is
+// refunded. Afterwards payment holds its new status.
+func (s *midtransService) ExpirePayment(ctx context.Context, payment *models.Payment) error {
+	if payment.Status != "pending" {
+		return nil
+	}
+
+	status, err := s.gateway.GetStatus(ctx, payment.MidtransOrderID)
+	if err == nil {
+		if newStatus, ok := paymentStatusFor(status.TransactionStatus, status.FraudStatus); ok && newStatus == "pending" {
+			status, err = s.gateway.Expire(ctx, payment.MidtransOrderID)
+		}
+	}
+	switch {
+	case errors.Is(err, gateway.ErrTransactionNotFound):
+		expired := *payment
+		expired.Status = "expired"
+		applied, err := s.paymentRepo.TransitionPaymentStatus(ctx, &expired, "pending")
+		if err != nil {
+			return fmt.Errorf("failed to update payment status: %v", err)
+		}
+		if !applied {
+			return fmt.Errorf("payment %s changed concurrently", payment.MidtransOrderID)
+		}
+		*payment = expired
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to expire transaction: %v", err)
+	}
+
+	if !amountMatches(status.GrossAmount, payment.Amount) {
+		return ErrAmountMismatch
+	}
+	return s.applyTransactionStatus(ctx, payment, *status)
+}
+
+// RefreshPaymentStatus asks Midtrans for the current status of a user's payment
+// and applies it, for when a notification never arrived.
+func (s *midtransService) RefreshPaymentStatus(ctx context.Context, paymentID uint, userID uint) (*models.Payment, error) {
+	payment, err := s.paymentRepo.GetPaymentByID(ctx, paymentID, userID)
+	if err != nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	if err := s.reconcilePayment(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// ReconcilePendingPayments re-checks every payment still pending since before createdBefore.
+// It returns how many payments changed status.
+func (s *midtransService) ReconcilePendingPayments(ctx context.Context, createdBefore time.Time) (int, error) {
+	payments, err := s.paymentRepo.GetPendingPaymentsCreatedBefore(ctx, createdBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending payments: %v", err)
+	}
+
+	updated := 0
+	for i := range payments {
+		payment := &payments[i]
+		if err := s.reconcilePayment(ctx, payment); err != nil {
+			fmt.Printf("ERROR reconciling OrderID %s: %v\n", payment.MidtransOrderID, err)
+			continue
+		}
+		if payment.Status != "pending" {
+			updated++
+		}
+	}
+
+	return updated, nil
+}
+
+// reconcilePayment pulls the transaction status from Midtrans and applies it to a pending payment.
+func (s *midtransService) reconcilePayment(ctx context.Context, payment *models.Payment) error {
+	if payment.Status != "pending" {
+		return nil
+	}
+
+	status, err := s.gateway.GetStatus(ctx, payment.MidtransOrderID)
+	if errors.Is(err, gateway.ErrTransactionNotFound) {
+		// The customer has not started paying yet
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get transaction status: %v", err)
+	}
+
+	if !amountMatches(status.GrossAmount, payment.Amount) {
+		return ErrAmountMismatch
+	}
+
+	return s.applyTransactionStatus(ctx, payment, *status)
+}
+
+// applySplitPayment settles a paid share, or the remainder the organiser covered. Money
+// that is no longer needed (the reservation was confirmed, cancelled or expired in the
+// meantime, or shares were paid twice) is refunded straight away.
+func (s *midtransService) applySplitPayment(ctx context.Context, payment *models.Payment) error {
+	paidAt := payment.PaymentTime
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	var surplus money.Amount
+	switch payment.Purpose {
+	case "share":
+		confirmed, err := s.participantRepo.MarkSharePaid(ctx, *payment.ParticipantID, paidAt)
+		switch {
+		case errors.Is(err, repositories.ErrSplitClosed):
+			surplus = payment.Amount - payment.Fee
+		case err != nil:
+			fmt.Printf("ERROR updating participant: %v\n", err)
+			return fmt.Errorf("failed to record share: %v", err)
+		case confirmed:
+			fmt.Printf("All shares paid, reservation %d updated to 'confirmed'\n", payment.ReservationID)
+		}
+	case "remainder":
+		covered, err := s.participantRepo.CoverRemainder(ctx, payment.ReservationID, paidAt)
+		switch {
+		case errors.Is(err, repositories.ErrSplitClosed):
+			surplus = payment.Amount - payment.Fee
+		case err != nil:
+			fmt.Printf("ERROR covering shares: %v\n", err)
+			return fmt.Errorf("failed to record remainder: %v", err)
+		default:
+			surplus = payment.Amount - payment.Fee - covered
+			fmt.Printf("Organiser covered the remaining shares, reservation %d updated to 'confirmed'\n", payment.ReservationID)
+		}
+	}
+
+	if surplus > 0 {
+		refund, err := s.RefundPayment(ctx, payment, surplus, "Share no longer needed for the reservation")
+		if err != nil && refund == nil {
+			fmt.Printf("❌ ERROR refunding surplus of OrderID %s: %v\n", payment.MidtransOrderID, err)
+			return err
+		}
+		if err != nil {
+			fmt.Printf("❌ ERROR refunding surplus of OrderID %s, will retry: %v\n", payment.MidtransOrderID, err)
+		}
+	}
+	return nil
+}
+
+// RefundPayment refunds amount of a paid payment through Midtrans and records the refund.
+// The payment becomes refunded or partially_refunded depending on how much of it has been
+// refunded so far; a partially refunded series payment can be refunded again. When the
+// gateway rejects the refund, it is returned with the error and stays pending for
+// RetryPendingRefunds.
+func (s *midtransService) RefundPayment(ctx context.Context, payment *models.Payment, amount money.Amount, reason string) (*models.Refund, error) {
+	if payment.Status != "paid" && payment.Status != "partially_refunded" {
+		return nil, fmt.Errorf("%w: payment is %s", ErrRefundFailed, payment.Status)
+	}
+
+	refunded, err := s.refundRepo.GetRefundedAmount(ctx, payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load refunds: %v", err)
+	}
+	if refunded+amount > payment.Amount-payment.Fee {
+		return nil, fmt.Errorf("%w: only %d of the payment is left to refund", ErrRefundFailed, payment.Amount-payment.Fee-refunded)
+	}
+
+	refund := newRefund(payment, amount, reason)
+	if err := s.refundRepo.CreateRefund(ctx, refund); err != nil {
+		return nil, fmt.Errorf("failed to save refund: %v", err)
+	}
+
+	if err := s.submitRefund(ctx, payment, refund); err != nil {
+		return refund, err
+	}
+	return refund, nil
+}
+
+// RetryPendingRefunds sends refunds the gateway has not accepted yet again, if their last
+// attempt was before updatedBefore. It returns how many of them succeeded.
+func (s *midtransService) RetryPendingRefunds(ctx context.Context, updatedBefore time.Time) (int, error) {
+	refunds, err := s.refundRepo.GetPendingRefundsUpdatedBefore(ctx, updatedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load pending refunds: %v", err)
+	}
+
+	succeeded := 0
+	for i := range refunds {
+		refund := &refunds[i]
+		if refund.Payment == nil {
+			continue
+		}
+		if err := s.submitRefund(ctx, refund.Payment, refund); err != nil {
+			fmt.Printf("ERROR retrying refund %s: %v\n", refund.RefundKey, err)
+			continue
+		}
+		succeeded++
+	}
+
+	return succeeded, nil
+}
+
+// submitRefund sends a saved pending refund to the gateway under its refund key, so a
+// retry of a refund the gateway did process is not paid out twice. A rejected refund stays
+// pending until it has been tried maxRefundAttempts times.
+func (s *midtransService) submitRefund(ctx context.Context, payment *models.Payment, refund *models.Refund) error {
+	fmt.Printf("🔄 Refunding %d for OrderID: %s\n", refund.Amount, payment.MidtransOrderID)
+	refund.Attempts++
+	refundErr := s.gateway.Refund(ctx, gateway.RefundRequest{
+		OrderID:   payment.MidtransOrderID,
+		RefundKey: refund.RefundKey,
+		Amount:    refund.Amount,
+		Reason:    refund.Reason,
+	})
+	if refundErr != nil {
+		fmt.Printf("❌ ERROR refunding OrderID %s (attempt %d): %v\n", payment.MidtransOrderID, refund.Attempts, refundErr)
+		refund.FailureReason = refundErr.Error()
+		if refund.Attempts >= maxRefundAttempts {
+			refund.Status = "failed"
+		}
+		if err := s.refundRepo.UpdateRefund(ctx, refund); err != nil {
+			fmt.Printf("ERROR saving refund: %v\n", err)
+		}
+		return fmt.Errorf("%w: %v", ErrRefundFailed, refundErr)
+	}
+
+	refund.Status = "succeeded"
+	refund.FailureReason = ""
+	if err := s.refundRepo.UpdateRefund(ctx, refund); err != nil {
+		return fmt.Errorf("failed to save refund: %v", err)
+	}
+
+	// The channel fee is not refundable, so a refund of the booking amount is a full refund
+	refunded, err := s.refundRepo.GetSucceededRefundAmount(ctx, payment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to load refunds: %v", err)
+	}
+	fromStatus := payment.Status
+	payment.Status = "partially_refunded"
+	if refunded >= payment.Amount-payment.Fee {
+		payment.Status = "refunded"
+	}
+	if fromStatus != payment.Status {
+		if _, err := s.paymentRepo.TransitionPaymentStatus(ctx, payment, fromStatus); err != nil {
+			return fmt.Errorf("failed to update payment status: %v", err)
+		}
+	}
+
+	fmt.Printf("✅ Refund %s succeeded, payment is now %s\n", refund.RefundKey, payment.Status)
+	return nil
+}
+
+// newRefund prepares a pending refund of amount for the payment.
+func newRefund(payment *models.Payment, amount money.Amount, reason string) *models.Refund {
+	return &models.Refund{
+		PaymentID:     payment.ID,
+		ReservationID: payment.ReservationID,
+		Amount:        amount,
+		Reason:        reason,
+		Status:        "pending",
+		RefundKey:     refundKeyFor(payment),
+	}
+}
+
+// refundKeyFor builds a new refund key for the payment. Midtrans needs a key that is unique
+// per refund, and one payment can be refunded several times within the same second.
+func refundKeyFor(payment *models.Payment) string {
+	return fmt.Sprintf("REFUND-%d-%d", payment.ID, time.Now().UnixNano())
+}
+
+// amountMatches compares Midtrans' gross_amount string (e.g. "150000.00") with the stored amount.
+func amountMatches(grossAmount string, expected money.Amount) bool {
+	amount, err := money.ParseGrossAmount(grossAmount)
+	if err != nil {
+		return false
+	}
+	return amount == expected
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBookingRestricted is returned when a user has missed too many bookings to book again.
+var ErrBookingRestricted = errors.New("booking is restricted after repeated no-shows")
+
+// NoShowPolicy blocks new bookings for users with Limit or more no-shows that started
+// within the last Window. A Limit of 0 turns the restriction off. Staff can still book
+// for a restricted customer at the front desk.
+type NoShowPolicy struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Status counts the user's recent no-shows.
+func (p NoShowPolicy) Status(ctx context.Context, reservationRepo repositories.ReservationRepository, userID uint) (*models.NoShowStatus, error) {
+	count, err := reservationRepo.CountNoShows(ctx, userID, time.Now().Add(-p.Window))
+	if err != nil {
+		return nil, errors.New("failed to check booking history")
+	}
+
+	return &models.NoShowStatus{
+		Count:      count,
+		Limit:      p.Limit,
+		WindowDays: int(p.Window.Hours() / 24),
+		Restricted: p.Limit > 0 && count >= int64(p.Limit),
+	}, nil
+}
+
+// Check returns ErrBookingRestricted when the user may not book.
+func (p NoShowPolicy) Check(ctx context.Context, reservationRepo repositories.ReservationRepository, userID uint) error {
+	if p.Limit <= 0 {
+		return nil
+	}
+
+	status, err := p.Status(ctx, reservationRepo, userID)
+	if err != nil {
+		return err
+	}
+	if status.Restricted {
+		return fmt.Errorf("%w: %d missed bookings in the last %d days", ErrBookingRestricted, status.Count, status.WindowDays)
+	}
+	return nil
+}
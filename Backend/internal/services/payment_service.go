@@ -3,291 +3,363 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/pkg/money"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"time"
 
-	"github.com/midtrans/midtrans-go"
-	"github.com/midtrans/midtrans-go/coreapi"
-	"github.com/midtrans/midtrans-go/snap"
+	"gorm.io/gorm"
 )
 
-type MidtransService interface {
-	CreatePayment(ctx context.Context, reservation *models.Reservation, user *models.User, paymentMethod string) (*PaymentResponse, error)
-	HandleNotification(ctx context.Context, payload map[string]interface{}) error
-}
+var (
+	// ErrReservationNotFound is returned when the reservation to pay does not exist.
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrNotReservationOwner is returned when paying for another user's reservation.
+	ErrNotReservationOwner = errors.New("reservation does not belong to user")
+	// ErrReservationNotPayable is returned when the reservation is no longer awaiting payment.
+	ErrReservationNotPayable = errors.New("reservation is not awaiting payment")
+	// ErrAlreadyPaid is returned when the reservation already has a settled payment.
+	ErrAlreadyPaid = errors.New("reservation is already paid")
+	// ErrPaymentInProgress is returned when a pending payment with another method exists.
+	ErrPaymentInProgress = errors.New("a payment with another method is already in progress")
+	// ErrActivePaymentExists is returned when a concurrent request created the pending payment first.
+	ErrActivePaymentExists = repositories.ErrActivePaymentExists
+	// ErrUnsupportedPaymentMethod is returned when the method or bank is not an enabled channel.
+	ErrUnsupportedPaymentMethod = errors.New("payment method is not available")
+	// ErrSeriesNotFound is returned when the reservation series does not exist.
+	ErrSeriesNotFound = errors.New("reservation series not found")
+	// ErrNotSeriesOwner is returned when accessing another user's reservation series.
+	ErrNotSeriesOwner = errors.New("reservation series does not belong to user")
+	// ErrNotParticipant is returned when a user who is not playing pays for a split reservation.
+	ErrNotParticipant = errors.New("user is not a participant of this reservation")
+)
 
-type PaymentResponse struct {
-	SnapToken   string                  `json:"snap_token,omitempty"`
-	VaNumber    string                  `json:"va_number,omitempty"`
-	VaBank      string                  `json:"va_bank,omitempty"`
-	RedirectURL string                  `json:"redirect_url,omitempty"`
-	CoreAPIResp *coreapi.ChargeResponse `json:"core_api_response,omitempty"`
-	SnapResp    *snap.Response          `json:"snap_response,omitempty"`
-	OrderID     string                  `json:"order_id"`
-	Amount      int64                   `json:"amount"`
-	Status      string                  `json:"status"`
+// PaymentService validates payment requests before handing them to Midtrans.
+type PaymentService interface {
+	CreatePayment(ctx context.Context, userID uint, req *models.CreatePaymentRequest) (*PaymentResponse, error)
+	CreateSeriesPayment(ctx context.Context, userID uint, req *models.CreateSeriesPaymentRequest) (*PaymentResponse, error)
+	GetPaymentMethods(ctx context.Context) ([]models.PaymentChannelResponse, error)
 }
 
-type midtransService struct {
-	coreClient      coreapi.Client
-	snapClient      snap.Client
-	paymentRepo     repositories.PaymentRepository
+type paymentService struct {
+	midtransService MidtransService
 	reservationRepo repositories.ReservationRepository
+	seriesRepo      repositories.SeriesRepository
+	participantRepo repositories.ParticipantRepository
+	userRepo        repositories.UserRepository
+	paymentRepo     repositories.PaymentRepository
+	channelRepo     repositories.PaymentChannelRepository
 }
 
-func NewMidtransService(paymentRepo repositories.PaymentRepository, reservationRepo repositories.ReservationRepository) MidtransService {
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-	if serverKey == "" {
-		panic("MIDTRANS_SERVER_KEY is not set")
+func NewPaymentService(
+	midtransService MidtransService,
+	reservationRepo repositories.ReservationRepository,
+	seriesRepo repositories.SeriesRepository,
+	participantRepo repositories.ParticipantRepository,
+	userRepo repositories.UserRepository,
+	paymentRepo repositories.PaymentRepository,
+	channelRepo repositories.PaymentChannelRepository,
+) PaymentService {
+	return &paymentService{
+		midtransService: midtransService,
+		reservationRepo: reservationRepo,
+		seriesRepo:      seriesRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		paymentRepo:     paymentRepo,
+		channelRepo:     channelRepo,
 	}
+}
 
-	// Initialize both clients
-	coreClient := coreapi.Client{}
-	coreClient.New(serverKey, midtrans.Sandbox)
-
-	snapClient := snap.Client{}
-	snapClient.New(serverKey, midtrans.Sandbox)
+// GetPaymentMethods lists the enabled payment channels with their fees.
+func (s *paymentService) GetPaymentMethods(ctx context.Context) ([]models.PaymentChannelResponse, error) {
+	channels, err := s.channelRepo.GetEnabledChannels(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get payment methods")
+	}
 
-	return &midtransService{
-		coreClient:      coreClient,
-		snapClient:      snapClient,
-		paymentRepo:     paymentRepo,
-		reservationRepo: reservationRepo,
+	methods := make([]models.PaymentChannelResponse, 0, len(channels))
+	for _, channel := range channels {
+		methods = append(methods, models.PaymentChannelResponse{
+			Code:          channel.Code,
+			Name:          channel.Name,
+			PaymentMethod: channel.PaymentMethod,
+			Bank:          channel.Bank,
+			FeeFlat:       channel.FeeFlat,
+			FeePercent:    channel.FeePercent,
+		})
 	}
+	return methods, nil
 }
 
-func (s *midtransService) CreatePayment(ctx context.Context, reservation *models.Reservation, user *models.User, paymentMethod string) (*PaymentResponse, error) {
-	amount := int64(reservation.TotalAmount)
-	orderID := fmt.Sprintf("ORDER-%d-%d", reservation.ID, reservation.CreatedAt.Unix())
+// CreatePayment starts a payment for the user's pending reservation, or the top-up owed
+// by a confirmed reservation moved to a pricier slot. For a split reservation it charges
+// the user's share instead. If a pending payment with the same method already exists,
+// its Snap token or VA is returned again.
+func (s *paymentService) CreatePayment(ctx context.Context, userID uint, req *models.CreatePaymentRequest) (*PaymentResponse, error) {
+	channel, err := s.resolveChannel(ctx, req.PaymentMethod, req.Bank)
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Printf("🎯 Creating payment for reservation %d, method: %s, amount: %d\n",
-		reservation.ID, paymentMethod, amount)
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, req.ReservationID)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
 
-	// Gunakan SNAP untuk semua payment method kecuali bank transfer
-	if paymentMethod != "bank_transfer" {
-		fmt.Printf("🔄 Using SNAP for payment method: %s\n", paymentMethod)
-		return s.createSnapPayment(ctx, reservation, user, paymentMethod, amount, orderID)
+	// Players of a split reservation each pay their own share
+	if reservation.SplitDeadline != nil && reservation.Status == "pending" {
+		return s.createSplitPayment(ctx, userID, reservation, channel)
 	}
 
-	// Untuk bank transfer, gunakan Core API
-	fmt.Printf("🔄 Using CoreAPI for bank transfer\n")
-	return s.createCoreAPIPayment(ctx, reservation, user, paymentMethod, amount, orderID)
-}
+	if reservation.UserID != userID {
+		return nil, ErrNotReservationOwner
+	}
 
-// Untuk Gopay, QRIS, Credit Card, etc. (Snap Popup)
-func (s *midtransService) createSnapPayment(ctx context.Context, reservation *models.Reservation, user *models.User, paymentMethod string, amount int64, orderID string) (*PaymentResponse, error) {
-
-	// Map payment method to Snap payment type
-	var enabledPayments []snap.SnapPaymentType
-	switch paymentMethod {
-	case "gopay":
-		enabledPayments = []snap.SnapPaymentType{snap.PaymentTypeGopay}
-	case "qris":
-		enabledPayments = []snap.SnapPaymentType{"qris"} // ✅ FIXED
-	case "credit_card":
-		enabledPayments = []snap.SnapPaymentType{snap.PaymentTypeCreditCard}
-	case "shopeepay":
-		enabledPayments = []snap.SnapPaymentType{snap.PaymentTypeShopeepay}
-	default:
-		// Default: enable semua payment methods
-		enabledPayments = []snap.SnapPaymentType{
-			snap.PaymentTypeGopay,
-			"qris", // ✅ FIXED
-			snap.PaymentTypeCreditCard,
-			snap.PaymentTypeShopeepay,
-		}
+	purpose := "booking"
+	switch {
+	case reservation.Status == "confirmed" && reservation.AmountDue > 0:
+		purpose = "top_up"
+	case reservation.Status != "pending":
+		return nil, ErrReservationNotPayable
+	case reservation.HoldExpiresAt != nil && !reservation.HoldExpiresAt.After(time.Now()):
+		return nil, fmt.Errorf("%w: reservation hold has expired", ErrReservationNotPayable)
+	case reservation.ClosureID != nil:
+		return nil, fmt.Errorf("%w: court is closed during this reservation", ErrReservationNotPayable)
+	case reservation.SeriesID != nil:
+		return nil, fmt.Errorf("%w: reservation is part of series %d, pay for the series instead", ErrReservationNotPayable, *reservation.SeriesID)
 	}
 
-	fmt.Printf("✅ Enabled payments for Snap: %v\n", enabledPayments)
-
-	snapReq := &snap.Request{
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  orderID,
-			GrossAmt: amount,
-		},
-		EnabledPayments: enabledPayments,
-		CustomerDetail: &midtrans.CustomerDetails{
-			FName: user.Name,
-			Email: user.Email,
-			Phone: user.Phone,
-		},
-		Items: &[]midtrans.ItemDetails{
-			{
-				ID:    fmt.Sprintf("COURT-%d", reservation.CourtID),
-				Price: amount,
-				Qty:   1,
-				Name:  fmt.Sprintf("Court Booking - %s", reservation.TimeSlot),
-			},
-		},
-	}
-
-	fmt.Printf("🔄 Sending Snap request for order: %s\n", orderID)
-	snapResp, err := s.snapClient.CreateTransaction(snapReq)
+	// Reuse an existing payment instead of creating a second Midtrans order.
+	// For a top-up, the settled booking payment does not count.
+	existing, err := s.paymentRepo.GetActivePaymentByReservationID(ctx, reservation.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing payments: %v", err)
+	}
+	if existing != nil && existing.Purpose != purpose {
+		existing = nil
+	}
+	if response, found, err := s.reusePayment(ctx, existing, channel); found {
+		return response, err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		fmt.Printf("❌ ERROR creating Snap transaction: %v\n", err)
-		return nil, fmt.Errorf("failed to create Snap transaction: %v", err)
+		return nil, errors.New("user not found")
 	}
 
-	fmt.Printf("✅ Snap response received. Token: %s, RedirectURL: %s\n",
-		snapResp.Token, snapResp.RedirectURL)
+	response, err := s.midtransService.CreatePayment(ctx, reservation, user, channel)
+	if errors.Is(err, ErrActivePaymentExists) {
+		// A concurrent request opened the order first; hand back that one instead
+		existing, lookupErr := s.paymentRepo.GetActivePaymentByPurpose(ctx, reservation.ID, purpose)
+		if lookupErr != nil {
+			return nil, ErrPaymentInProgress
+		}
+		if response, found, err := s.reusePayment(ctx, existing, channel); found {
+			return response, err
+		}
+		return nil, ErrPaymentInProgress
+	}
+	return response, err
+}
 
-	// Save to database
-	payment := &models.Payment{
-		ReservationID:   reservation.ID,
-		Amount:          float64(amount),
-		Status:          "pending",
-		PaymentMethod:   paymentMethod,
-		MidtransOrderID: orderID,
-		VaNumber:        "", // Tidak ada VA untuk non-bank transfer
-		VaBank:          "",
+// CreateSeriesPayment starts one payment covering every occurrence of the user's pending
+// series. As with single reservations, a pending payment with the same method is reused.
+func (s *paymentService) CreateSeriesPayment(ctx context.Context, userID uint, req *models.CreateSeriesPaymentRequest) (*PaymentResponse, error) {
+	channel, err := s.resolveChannel(ctx, req.PaymentMethod, req.Bank)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.paymentRepo.CreatePayment(ctx, payment); err != nil {
-		fmt.Printf("❌ ERROR saving payment to database: %v\n", err)
-		return nil, fmt.Errorf("failed to save payment: %v", err)
+	series, err := s.seriesRepo.GetSeriesByID(ctx, req.SeriesID)
+	if err != nil {
+		return nil, ErrSeriesNotFound
+	}
+	if series.UserID != userID {
+		return nil, ErrNotSeriesOwner
+	}
+	if series.Status != "pending" {
+		return nil, ErrReservationNotPayable
 	}
 
-	fmt.Printf("✅ Payment saved to database with ID: %d\n", payment.ID)
+	// Every occurrence must still be held; otherwise the series has to be booked again
+	now := time.Now()
+	occurrences := make([]models.Reservation, 0, len(series.Reservations))
+	for _, occurrence := range series.Reservations {
+		switch {
+		case occurrence.Status == "cancelled":
+			continue
+		case occurrence.Status != "pending",
+			occurrence.HoldExpiresAt != nil && !occurrence.HoldExpiresAt.After(now):
+			return nil, fmt.Errorf("%w: the hold on the %s occurrence has expired", ErrReservationNotPayable, occurrence.ReservationDate.Format("2006-01-02"))
+		case occurrence.ClosureID != nil:
+			return nil, fmt.Errorf("%w: court is closed on %s", ErrReservationNotPayable, occurrence.ReservationDate.Format("2006-01-02"))
+		}
+		occurrences = append(occurrences, occurrence)
+	}
 
-	return &PaymentResponse{
-		SnapToken:   snapResp.Token,
-		RedirectURL: snapResp.RedirectURL,
-		SnapResp:    snapResp,
-		OrderID:     orderID,
-		Amount:      amount,
-		Status:      "pending",
-	}, nil
-}
+	existing, err := s.paymentRepo.GetActivePaymentBySeriesID(ctx, series.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing payments: %v", err)
+	}
+	if response, found, err := s.reusePayment(ctx, existing, channel); found {
+		return response, err
+	}
 
-// Untuk Bank Transfer (Core API)
-func (s *midtransService) createCoreAPIPayment(ctx context.Context, reservation *models.Reservation, user *models.User, paymentMethod string, amount int64, orderID string) (*PaymentResponse, error) {
-
-	chargeReq := &coreapi.ChargeReq{
-		PaymentType: coreapi.PaymentTypeBankTransfer,
-		TransactionDetails: midtrans.TransactionDetails{
-			OrderID:  orderID,
-			GrossAmt: amount,
-		},
-		BankTransfer: &coreapi.BankTransferDetails{
-			Bank: midtrans.BankBca,
-		},
-		CustomerDetails: &midtrans.CustomerDetails{
-			FName: user.Name,
-			Email: user.Email,
-			Phone: user.Phone,
-		},
-		Items: &[]midtrans.ItemDetails{
-			{
-				ID:    fmt.Sprintf("COURT-%d", reservation.CourtID),
-				Price: amount,
-				Qty:   1,
-				Name:  fmt.Sprintf("Court Booking - %s", reservation.TimeSlot),
-			},
-		},
-	}
-
-	fmt.Printf("🔄 Sending CoreAPI request for bank transfer, order: %s\n", orderID)
-	coreResp, err := s.coreClient.ChargeTransaction(chargeReq)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
-		fmt.Printf("❌ ERROR creating CoreAPI transaction: %v\n", err)
-		return nil, fmt.Errorf("failed to create Core API transaction: %v", err)
+		return nil, errors.New("user not found")
 	}
 
-	fmt.Printf("✅ CoreAPI response received. Status: %s\n", coreResp.StatusMessage)
+	response, err := s.midtransService.CreateSeriesPayment(ctx, series, occurrences, user, channel)
+	if errors.Is(err, ErrActivePaymentExists) {
+		// A concurrent request opened the order first; hand back that one instead
+		existing, lookupErr := s.paymentRepo.GetActivePaymentBySeriesID(ctx, series.ID)
+		if lookupErr != nil {
+			return nil, ErrPaymentInProgress
+		}
+		if response, found, err := s.reusePayment(ctx, existing, channel); found {
+			return response, err
+		}
+		return nil, ErrPaymentInProgress
+	}
+	return response, err
+}
 
-	// Save VA Info
-	vaNumber := ""
-	vaBank := ""
+// createSplitPayment charges the user's share of a split reservation until the deadline.
+// After it, only the organiser can pay, covering every share still unpaid.
+func (s *paymentService) createSplitPayment(ctx context.Context, userID uint, reservation *models.Reservation, channel *models.PaymentChannel) (*PaymentResponse, error) {
+	if reservation.HoldExpiresAt != nil && !reservation.HoldExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: reservation hold has expired", ErrReservationNotPayable)
+	}
+	if reservation.ClosureID != nil {
+		return nil, fmt.Errorf("%w: court is closed during this reservation", ErrReservationNotPayable)
+	}
 
-	if len(coreResp.VaNumbers) > 0 {
-		vaNumber = coreResp.VaNumbers[0].VANumber
-		vaBank = coreResp.VaNumbers[0].Bank
-		fmt.Printf("✅ VA Number: %s, Bank: %s\n", vaNumber, vaBank)
+	participant, err := s.participantRepo.GetParticipant(ctx, reservation.ID, userID)
+	if err != nil {
+		return nil, ErrNotParticipant
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
 	}
 
-	payment := &models.Payment{
-		ReservationID:   reservation.ID,
-		Amount:          float64(amount),
-		Status:          "pending",
-		PaymentMethod:   paymentMethod,
-		MidtransOrderID: orderID,
-		VaNumber:        vaNumber,
-		VaBank:          vaBank,
+	if time.Now().Before(*reservation.SplitDeadline) {
+		if participant.Status != "pending" {
+			return nil, fmt.Errorf("%w: your share is %s", ErrAlreadyPaid, participant.Status)
+		}
+
+		existing, err := s.paymentRepo.GetActivePaymentByParticipantID(ctx, participant.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to check existing payments: %v", err)
+		}
+		if response, found, err := s.reusePayment(ctx, existing, channel); found {
+			return response, err
+		}
+		return s.midtransService.CreateSharePayment(ctx, reservation, participant, user, channel)
 	}
 
-	if err := s.paymentRepo.CreatePayment(ctx, payment); err != nil {
-		fmt.Printf("❌ ERROR saving payment to database: %v\n", err)
-		return nil, fmt.Errorf("failed to save payment: %v", err)
+	if reservation.UserID != userID {
+		return nil, fmt.Errorf("%w: the deadline to pay your share has passed", ErrReservationNotPayable)
 	}
 
-	fmt.Printf("✅ Bank transfer payment saved to database with ID: %d\n", payment.ID)
+	participants, err := s.participantRepo.GetParticipants(ctx, reservation.ID)
+	if err != nil {
+		return nil, errors.New("failed to get participants")
+	}
+	var remaining money.Amount
+	for _, participant := range participants {
+		if participant.Status == "pending" {
+			remaining += participant.ShareAmount
+		}
+	}
+	if remaining == 0 {
+		return nil, ErrAlreadyPaid
+	}
 
-	return &PaymentResponse{
-		VaNumber:    vaNumber,
-		VaBank:      vaBank,
-		CoreAPIResp: coreResp,
-		OrderID:     orderID,
-		Amount:      amount,
-		Status:      "pending",
-	}, nil
+	existing, err := s.paymentRepo.GetActivePaymentByPurpose(ctx, reservation.ID, "remainder")
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to check existing payments: %v", err)
+	}
+	if response, found, err := s.reusePayment(ctx, existing, channel); found {
+		return response, err
+	}
+	return s.midtransService.CreateRemainderPayment(ctx, reservation, remaining, user, channel)
 }
 
-// FIXED PARSING NOTIFICATION
-func (s *midtransService) HandleNotification(ctx context.Context, payload map[string]interface{}) error {
-	fmt.Printf("=== MIDTRANS NOTIFICATION RECEIVED ===\n")
-	fmt.Printf("Raw payload: %+v\n", payload)
-
-	jsonBytes, _ := json.Marshal(payload)
-	var notif coreapi.TransactionStatusResponse
-	if err := json.Unmarshal(jsonBytes, &notif); err != nil {
-		fmt.Printf("ERROR parsing notification: %v\n", err)
-		return fmt.Errorf("failed to parse Midtrans notification: %v", err)
-	}
-
-	fmt.Printf("Parsed notification - OrderID: %s, Status: %s\n", notif.OrderID, notif.TransactionStatus)
-
-	var newStatus string
-	switch notif.TransactionStatus {
-	case "settlement":
-		newStatus = "paid"
-		fmt.Printf("Setting payment status to: %s\n", newStatus)
-	case "pending":
-		newStatus = "pending"
-	case "expire":
-		newStatus = "expired"
-	case "cancel", "deny":
-		newStatus = "failed"
-	default:
-		newStatus = "failed"
+// reusePayment returns the Snap token or VA of an existing pending payment with the same
+// method, so a retried request does not create a second Midtrans order. It reports false
+// when there is no payment to reuse; a pending payment whose order has lapsed is marked
+// expired first so a new one can be opened.
+func (s *paymentService) reusePayment(ctx context.Context, existing *models.Payment, channel *models.PaymentChannel) (*PaymentResponse, bool, error) {
+	if existing != nil && existing.Status == "pending" && existing.ExpiresAt != nil && !existing.ExpiresAt.After(time.Now()) {
+		// Closed through the gateway, so a settlement still in flight is not lost
+		if err := s.midtransService.ExpirePayment(ctx, existing); err != nil {
+			fmt.Printf("ERROR expiring lapsed payment %s: %v\n", existing.MidtransOrderID, err)
+			return nil, true, fmt.Errorf("%w: payment changed, try again", ErrReservationNotPayable)
+		}
+		switch existing.Status {
+		case "expired", "failed":
+			existing = nil
+		case "pending":
+			return nil, true, fmt.Errorf("%w: payment changed, try again", ErrReservationNotPayable)
+		}
 	}
 
-	// Update payment
-	fmt.Printf("Updating payment for OrderID: %s to status: %s\n", notif.OrderID, newStatus)
-	if err := s.paymentRepo.UpdatePaymentStatus(ctx, notif.OrderID, newStatus); err != nil {
-		fmt.Printf("ERROR updating payment status: %v\n", err)
-		return fmt.Errorf("failed to update payment status: %v", err)
+	switch {
+	case existing == nil:
+		return nil, false, nil
+	case existing.Status == "paid":
+		return nil, true, ErrAlreadyPaid
+	case existing.PaymentMethod != channel.PaymentMethod,
+		channel.Bank != "" && existing.VaBank != channel.Bank:
+		return nil, true, ErrPaymentInProgress
+	default:
+		return paymentResponseFrom(existing), true, nil
 	}
-	fmt.Printf("Payment updated successfully\n")
+}
 
-	// Update reservation if paid
-	if newStatus == "paid" {
-		fmt.Printf("Payment is PAID, updating reservation...\n")
-		pay, err := s.paymentRepo.GetPaymentByOrderID(ctx, notif.OrderID)
-		if err != nil {
-			fmt.Printf("ERROR getting payment: %v\n", err)
-			return fmt.Errorf("failed to get payment by order ID: %v", err)
+// resolveChannel validates the requested method (and VA bank) against the enabled catalogue.
+func (s *paymentService) resolveChannel(ctx context.Context, method, requestedBank string) (*models.PaymentChannel, error) {
+	bank := ""
+	if method == "bank_transfer" {
+		bank = strings.ToLower(requestedBank)
+		if bank == "" {
+			bank = "bca"
 		}
-		fmt.Printf("Found payment with ReservationID: %d\n", pay.ReservationID)
+	}
 
-		if err := s.reservationRepo.UpdateReservationStatus(ctx, pay.ReservationID, "confirmed"); err != nil {
-			fmt.Printf("ERROR updating reservation: %v\n", err)
-			return fmt.Errorf("failed to update reservation status: %v", err)
+	channel, err := s.channelRepo.GetEnabledChannel(ctx, method, bank)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if bank != "" {
+			return nil, fmt.Errorf("%w: bank_transfer via %s", ErrUnsupportedPaymentMethod, bank)
 		}
-		fmt.Printf("Reservation %d updated to 'confirmed'\n", pay.ReservationID)
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedPaymentMethod, method)
 	}
+	if err != nil {
+		return nil, errors.New("failed to check payment method")
+	}
+	return channel, nil
+}
 
-	fmt.Printf("=== NOTIFICATION PROCESSING COMPLETE ===\n")
-	return nil
+// channelFee is the channel's fee for amount, rounded to whole rupiah.
+func channelFee(channel *models.PaymentChannel, amount money.Amount) money.Amount {
+	return channel.FeeFlat + amount.PercentRounded(channel.FeePercent)
+}
+
+func paymentResponseFrom(payment *models.Payment) *PaymentResponse {
+	return &PaymentResponse{
+		SnapToken:   payment.SnapToken,
+		RedirectURL: payment.RedirectURL,
+		VaNumber:    payment.VaNumber,
+		VaBank:      payment.VaBank,
+		BillerCode:  payment.BillerCode,
+		OrderID:     payment.MidtransOrderID,
+		Amount:      payment.Amount,
+		Fee:         payment.Fee,
+		Status:      payment.Status,
+	}
 }
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"time"
+)
+
+// paymentTransitions lists the payment statuses reachable from each status.
+// Anything not listed (e.g. paid -> pending from an out-of-order webhook) is ignored.
+// An expired payment can still become paid when the money was already on its way as the
+// order was closed; that money is refunded.
+var paymentTransitions = map[string][]string{
+	"pending":            {"paid", "expired", "failed"},
+	"paid":               {"refunded", "partially_refunded"},
+	"partially_refunded": {"refunded"},
+	"expired":            {"paid"},
+	"failed":             {},
+	"refunded":           {},
+}
+
+func canTransitionPayment(from, to string) bool {
+	for _, next := range paymentTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// paymentStatusFor maps a Midtrans transaction_status (and fraud_status for card captures)
+// to our payment status. ok is false for statuses we don't act on.
+func paymentStatusFor(transactionStatus, fraudStatus string) (status string, ok bool) {
+	switch transactionStatus {
+	case "capture":
+		switch fraudStatus {
+		case "accept", "":
+			return "paid", true
+		case "challenge":
+			return "pending", true
+		default:
+			return "failed", true
+		}
+	case "settlement":
+		return "paid", true
+	case "pending", "authorize":
+		return "pending", true
+	case "expire":
+		return "expired", true
+	case "cancel", "deny", "failure":
+		return "failed", true
+	case "refund":
+		return "refunded", true
+	case "partial_refund":
+		return "partially_refunded", true
+	default:
+		return "", false
+	}
+}
+
+// midtransTimeLocation is the timezone Midtrans uses for its timestamps (WIB, GMT+7).
+var midtransTimeLocation = time.FixedZone("WIB", 7*60*60)
+
+// parseMidtransTime parses a Midtrans "2006-01-02 15:04:05" timestamp, falling back to now.
+func parseMidtransTime(value string) time.Time {
+	parsed, err := time.ParseInLocation("2006-01-02 15:04:05", value, midtransTimeLocation)
+	if err != nil {
+		return time.Now()
+	}
+	return parsed
+}
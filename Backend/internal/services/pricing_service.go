@@ -0,0 +1,197 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PricingService prices court time from the court's base price and its pricing rules.
+type PricingService interface {
+	Quote(ctx context.Context, court *models.Court, date time.Time, start, end int) (*PriceQuote, error)
+	DayRates(ctx context.Context, court *models.Court, date time.Time) (*DayRates, error)
+	CurrentRules(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error)
+}
+
+// PriceQuote is the price of a booking, split into segments priced at a single rate.
+type PriceQuote struct {
+	Total money.Amount
+	Lines []models.ReservationPriceLine
+}
+
+type pricingService struct {
+	pricingRepo repositories.PricingRepository
+}
+
+func NewPricingService(pricingRepo repositories.PricingRepository) PricingService {
+	return &pricingService{pricingRepo: pricingRepo}
+}
+
+func (s *pricingService) Quote(ctx context.Context, court *models.Court, date time.Time, start, end int) (*PriceQuote, error) {
+	rates, err := s.DayRates(ctx, court, date)
+	if err != nil {
+		return nil, err
+	}
+	return rates.Quote(start, end)
+}
+
+// DayRates loads the rules in effect for a court on date, so several ranges on the
+// same day can be priced without querying again.
+func (s *pricingService) DayRates(ctx context.Context, court *models.Court, date time.Time) (*DayRates, error) {
+	rules, err := s.pricingRepo.GetRulesForDate(ctx, court.ID, date)
+	if err != nil {
+		return nil, errors.New("failed to load pricing rules")
+	}
+
+	holiday, err := s.pricingRepo.IsHoliday(ctx, date)
+	if err != nil {
+		return nil, errors.New("failed to check holidays")
+	}
+
+	rates := &DayRates{basePrice: court.PricePerHour}
+	for _, rule := range rules {
+		if !dayTypeMatches(rule.DayType, date, holiday) {
+			continue
+		}
+
+		// Rules are validated when saved; one that predates validation is skipped so it
+		// does not block every quote for the court
+		band, err := pricingBandFor(rule)
+		if err != nil {
+			fmt.Printf("⚠️ Skipping pricing rule %d of court %d: %v\n", rule.ID, court.ID, err)
+			continue
+		}
+		rates.bands = append(rates.bands, band)
+	}
+	return rates, nil
+}
+
+// CurrentRules lists the court's rules in effect on date or later, for display.
+func (s *pricingService) CurrentRules(ctx context.Context, courtID uint, date time.Time) ([]models.PricingRule, error) {
+	rules, err := s.pricingRepo.GetCurrentRules(ctx, courtID, date)
+	if err != nil {
+		return nil, errors.New("failed to load pricing rules")
+	}
+	return rules, nil
+}
+
+// DayRates holds a court's base price and the rules matching one date, highest priority first.
+type DayRates struct {
+	basePrice money.Amount
+	bands     []pricingBand
+}
+
+type pricingBand struct {
+	rule       models.PricingRule
+	start, end int
+}
+
+// Quote prices [start, end) in minutes since midnight. The range is split wherever a rule's
+// time band starts or ends, and each segment is charged at the best matching rule's rate.
+func (d *DayRates) Quote(start, end int) (*PriceQuote, error) {
+	if end <= start {
+		return nil, errors.New("invalid time range")
+	}
+
+	// Segment boundaries: the range itself and every band edge inside it
+	boundaries := []int{start, end}
+	for _, band := range d.bands {
+		for _, edge := range []int{band.start, band.end} {
+			if edge > start && edge < end {
+				boundaries = append(boundaries, edge)
+			}
+		}
+	}
+	sort.Ints(boundaries)
+
+	quote := &PriceQuote{}
+	var last *models.ReservationPriceLine
+	segmentStart := start
+	for _, boundary := range boundaries {
+		if boundary <= segmentStart {
+			continue
+		}
+
+		line := d.priceSegment(segmentStart, boundary)
+		// Merge with the previous segment when the same rate continues
+		if last != nil && sameRule(last.PricingRuleID, line.PricingRuleID) && last.PricePerHour == line.PricePerHour {
+			lastStart, _, _ := utils.ParseTimeSlot(last.TimeSlot)
+			last.TimeSlot = utils.FormatTimeSlot(lastStart, boundary)
+			last.Amount += line.Amount
+		} else {
+			quote.Lines = append(quote.Lines, line)
+			last = &quote.Lines[len(quote.Lines)-1]
+		}
+		segmentStart = boundary
+	}
+
+	for _, line := range quote.Lines {
+		quote.Total += line.Amount
+	}
+	return quote, nil
+}
+
+func (d *DayRates) priceSegment(start, end int) models.ReservationPriceLine {
+	line := models.ReservationPriceLine{
+		TimeSlot:     utils.FormatTimeSlot(start, end),
+		PricePerHour: d.basePrice,
+	}
+
+	// Bands are ordered by priority, so the first covering band wins
+	for _, band := range d.bands {
+		if band.start <= start && end <= band.end {
+			ruleID := band.rule.ID
+			line.PricePerHour = band.rule.PricePerHour
+			line.PricingRuleID = &ruleID
+			line.RuleName = band.rule.Name
+			break
+		}
+	}
+
+	line.Amount = line.PricePerHour * money.Amount(end-start) / 60
+	return line
+}
+
+func sameRule(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// dayTypeMatches reports whether a rule's day type applies to date.
+// Holidays are priced like weekends unless a holiday rule takes precedence.
+func dayTypeMatches(dayType string, date time.Time, holiday bool) bool {
+	weekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+
+	switch dayType {
+	case "":
+		return true
+	case "weekday":
+		return !weekend && !holiday
+	case "weekend":
+		return weekend || holiday
+	case "holiday":
+		return holiday
+	default:
+		return false
+	}
+}
+
+func pricingBandFor(rule models.PricingRule) (pricingBand, error) {
+	if rule.StartTime == "" && rule.EndTime == "" {
+		return pricingBand{rule: rule, start: 0, end: utils.MinutesPerDay}, nil
+	}
+
+	start, end, err := utils.ParseTimeSlot(rule.StartTime + "-" + rule.EndTime)
+	if err != nil {
+		return pricingBand{}, fmt.Errorf("invalid time band on pricing rule %d: %v", rule.ID, err)
+	}
+	return pricingBand{rule: rule, start: start, end: end}, nil
+}
@@ -0,0 +1,440 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// maxSeriesOccurrences caps a series at a year of weekly bookings.
+const maxSeriesOccurrences = 52
+
+// SeriesConflictError lists the occurrences of a series that cannot be booked. Nothing is
+// saved when it is returned.
+type SeriesConflictError struct {
+	Conflicts []models.SeriesConflict
+}
+
+func (e *SeriesConflictError) Error() string {
+	return fmt.Sprintf("%d occurrences of the series cannot be booked", len(e.Conflicts))
+}
+
+// ReservationSeriesService books recurring weekly or biweekly reservations. A series is
+// created, rescheduled and cancelled as a whole and paid with a single payment.
+type ReservationSeriesService interface {
+	CreateSeries(ctx context.Context, userID uint, req *models.CreateSeriesRequest) (*models.ReservationSeriesResponse, error)
+	GetUserSeries(ctx context.Context, userID uint) ([]models.ReservationSeriesResponse, error)
+	GetSeries(ctx context.Context, userID uint, id uint) (*models.ReservationSeriesResponse, error)
+	ModifySeries(ctx context.Context, userID uint, id uint, req *models.ModifySeriesRequest) (*models.ReservationSeriesResponse, error)
+	CancelSeries(ctx context.Context, userID uint, id uint) (*models.Refund, error)
+}
+
+type reservationSeriesService struct {
+	seriesRepo      repositories.SeriesRepository
+	reservationRepo repositories.ReservationRepository
+	courtRepo       repositories.CourtRepository
+	closureRepo     repositories.ClosureRepository
+	paymentRepo     repositories.PaymentRepository
+	midtransService MidtransService
+	pricingService  PricingService
+	noShowPolicy    NoShowPolicy
+	holdTTL         time.Duration
+}
+
+func NewReservationSeriesService(
+	seriesRepo repositories.SeriesRepository,
+	reservationRepo repositories.ReservationRepository,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	paymentRepo repositories.PaymentRepository,
+	midtransService MidtransService,
+	pricingService PricingService,
+	noShowPolicy NoShowPolicy,
+	holdTTL time.Duration,
+) ReservationSeriesService {
+	return &reservationSeriesService{
+		seriesRepo:      seriesRepo,
+		reservationRepo: reservationRepo,
+		courtRepo:       courtRepo,
+		closureRepo:     closureRepo,
+		paymentRepo:     paymentRepo,
+		midtransService: midtransService,
+		pricingService:  pricingService,
+		noShowPolicy:    noShowPolicy,
+		holdTTL:         holdTTL,
+	}
+}
+
+func toSeriesResponse(series *models.ReservationSeries) models.ReservationSeriesResponse {
+	occurrences := make([]models.ReservationResponse, 0, len(series.Reservations))
+	for i := range series.Reservations {
+		occurrences = append(occurrences, toReservationResponse(&series.Reservations[i]))
+	}
+
+	return models.ReservationSeriesResponse{
+		ID:          series.ID,
+		UserID:      series.UserID,
+		CourtIDs:    series.CourtIDs,
+		Frequency:   series.Frequency,
+		StartDate:   series.StartDate.Format("2006-01-02"),
+		EndDate:     series.EndDate.Format("2006-01-02"),
+		StartTime:   series.StartTime,
+		EndTime:     series.EndTime,
+		TotalAmount: series.TotalAmount,
+		Status:      series.Status,
+		CreatedAt:   series.CreatedAt,
+		Occurrences: occurrences,
+	}
+}
+
+// seriesDates lists the dates of a series: every week (or every other week) from the
+// start date until the end date or the requested number of occurrences.
+func seriesDates(req *models.CreateSeriesRequest) ([]time.Time, error) {
+	start, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, errors.New("invalid start_date format. Use YYYY-MM-DD")
+	}
+	// Courts are resolved per occurrence later; the date check uses the default venue timezone
+	today := venueToday(venueLocation(&models.Venue{Timezone: defaultVenueTimezone}))
+	if start.Before(today) {
+		return nil, errors.New("start_date cannot be in the past")
+	}
+
+	interval := 7
+	if req.Frequency == "biweekly" {
+		interval = 14
+	}
+
+	var dates []time.Time
+	switch {
+	case req.EndDate != "" && req.Occurrences > 0:
+		return nil, errors.New("provide either end_date or occurrences, not both")
+	case req.Occurrences > 0:
+		for i := 0; i < req.Occurrences; i++ {
+			dates = append(dates, start.AddDate(0, 0, i*interval))
+		}
+	case req.EndDate != "":
+		end, err := time.Parse("2006-01-02", req.EndDate)
+		if err != nil {
+			return nil, errors.New("invalid end_date format. Use YYYY-MM-DD")
+		}
+		if end.Before(start) {
+			return nil, errors.New("end_date cannot be before start_date")
+		}
+		for date := start; !date.After(end); date = date.AddDate(0, 0, interval) {
+			dates = append(dates, date)
+		}
+	default:
+		return nil, errors.New("provide end_date or occurrences")
+	}
+
+	if len(dates) > maxSeriesOccurrences {
+		return nil, fmt.Errorf("a series can have at most %d occurrences", maxSeriesOccurrences)
+	}
+	return dates, nil
+}
+
+// CreateSeries books every occurrence of the series on every requested court. All
+// occurrences are checked first; if any of them cannot be booked nothing is saved and a
+// SeriesConflictError lists them. Occurrences are held like single reservations until
+// the series payment completes.
+func (s *reservationSeriesService) CreateSeries(ctx context.Context, userID uint, req *models.CreateSeriesRequest) (*models.ReservationSeriesResponse, error) {
+	if err := s.noShowPolicy.Check(ctx, s.reservationRepo, userID); err != nil {
+		return nil, err
+	}
+
+	dates, err := seriesDates(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := utils.ParseTimeSlot(req.StartTime + "-" + req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	timeSlot := utils.FormatTimeSlot(start, end)
+
+	courtIDs := append([]uint(nil), req.CourtIDs...)
+	sort.Slice(courtIDs, func(i, j int) bool { return courtIDs[i] < courtIDs[j] })
+	for i := 1; i < len(courtIDs); i++ {
+		if courtIDs[i] == courtIDs[i-1] {
+			return nil, fmt.Errorf("court %d is listed more than once", courtIDs[i])
+		}
+	}
+
+	holdExpiresAt := time.Now().Add(s.holdTTL)
+	var reservations []models.Reservation
+	var conflicts []models.SeriesConflict
+	var total money.Amount
+	for _, date := range dates {
+		for _, courtID := range courtIDs {
+			occurrence := &models.CreateReservationRequest{
+				CourtID:   courtID,
+				Date:      date.Format("2006-01-02"),
+				StartTime: req.StartTime,
+				EndTime:   req.EndTime,
+			}
+			reservation, err := quoteReservation(ctx, s.courtRepo, s.closureRepo, s.reservationRepo, s.pricingService, occurrence)
+			if err != nil {
+				conflicts = append(conflicts, models.SeriesConflict{
+					Date:     occurrence.Date,
+					CourtID:  courtID,
+					TimeSlot: timeSlot,
+					Reason:   err.Error(),
+				})
+				continue
+			}
+
+			reservation.UserID = userID
+			reservation.Status = "pending" // Confirmed together once the series is paid
+			reservation.HoldExpiresAt = &holdExpiresAt
+			reservations = append(reservations, *reservation)
+			total += reservation.TotalAmount
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, &SeriesConflictError{Conflicts: conflicts}
+	}
+
+	series := &models.ReservationSeries{
+		UserID:      userID,
+		CourtIDs:    courtIDs,
+		Frequency:   req.Frequency,
+		StartDate:   dates[0],
+		EndDate:     dates[len(dates)-1],
+		StartTime:   utils.FormatClock(start),
+		EndTime:     utils.FormatClock(end),
+		TotalAmount: total,
+		Status:      "pending",
+	}
+
+	err = s.seriesRepo.CreateSeries(ctx, series, reservations)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		// Someone booked one of the slots after the check above
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, errors.New("failed to create reservation series")
+	}
+
+	return s.GetSeries(ctx, userID, series.ID)
+}
+
+func (s *reservationSeriesService) GetUserSeries(ctx context.Context, userID uint) ([]models.ReservationSeriesResponse, error) {
+	series, err := s.seriesRepo.GetUserSeries(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get reservation series")
+	}
+
+	responses := make([]models.ReservationSeriesResponse, 0, len(series))
+	for i := range series {
+		responses = append(responses, toSeriesResponse(&series[i]))
+	}
+	return responses, nil
+}
+
+func (s *reservationSeriesService) GetSeries(ctx context.Context, userID uint, id uint) (*models.ReservationSeriesResponse, error) {
+	series, err := s.loadSeries(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toSeriesResponse(series)
+	return &response, nil
+}
+
+func (s *reservationSeriesService) loadSeries(ctx context.Context, userID uint, id uint) (*models.ReservationSeries, error) {
+	series, err := s.seriesRepo.GetSeriesByID(ctx, id)
+	if err != nil {
+		return nil, ErrSeriesNotFound
+	}
+	if series.UserID != userID {
+		return nil, ErrNotSeriesOwner
+	}
+	return series, nil
+}
+
+// upcomingOccurrences returns the occurrences that still hold their court and have not
+// started yet; only those are moved or cancelled with the series.
+func upcomingOccurrences(series *models.ReservationSeries, now time.Time) []models.Reservation {
+	var upcoming []models.Reservation
+	for _, occurrence := range series.Reservations {
+		if occurrence.Status != "pending" && occurrence.Status != "confirmed" {
+			continue
+		}
+		if !occurrence.StartAt.After(now) {
+			continue
+		}
+		upcoming = append(upcoming, occurrence)
+	}
+	return upcoming
+}
+
+// ModifySeries moves every upcoming occurrence to a new start time on the same day and
+// court, keeping the duration and the price paid. Conflicts are reported per occurrence
+// and nothing is moved unless all of them fit.
+func (s *reservationSeriesService) ModifySeries(ctx context.Context, userID uint, id uint, req *models.ModifySeriesRequest) (*models.ReservationSeriesResponse, error) {
+	series, err := s.loadSeries(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == "cancelled" {
+		return nil, errors.New("reservation series is cancelled")
+	}
+
+	start, err := utils.ParseClock(req.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	oldStart, oldEnd, err := utils.ParseTimeSlot(series.StartTime + "-" + series.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	end := start + (oldEnd - oldStart)
+	if end > utils.MinutesPerDay {
+		return nil, errors.New("reservation cannot extend past midnight")
+	}
+	timeSlot := utils.FormatTimeSlot(start, end)
+
+	upcoming := upcomingOccurrences(series, time.Now())
+	if len(upcoming) == 0 {
+		return nil, errors.New("reservation series has no upcoming occurrences")
+	}
+
+	var conflicts []models.SeriesConflict
+	for i := range upcoming {
+		occurrence := &upcoming[i]
+		if err := s.checkMove(ctx, series, occurrence, start, end); err != nil {
+			conflicts = append(conflicts, models.SeriesConflict{
+				Date:     occurrence.ReservationDate.Format("2006-01-02"),
+				CourtID:  occurrence.CourtID,
+				TimeSlot: timeSlot,
+				Reason:   err.Error(),
+			})
+			continue
+		}
+
+		occurrence.TimeSlot = timeSlot
+		occurrence.StartAt = slotTime(occurrence.ReservationDate, start, courtLocation(&occurrence.Court))
+		occurrence.EndAt = slotTime(occurrence.ReservationDate, end, courtLocation(&occurrence.Court))
+	}
+	if len(conflicts) > 0 {
+		return nil, &SeriesConflictError{Conflicts: conflicts}
+	}
+
+	series.StartTime = utils.FormatClock(start)
+	series.EndTime = utils.FormatClock(end)
+	err = s.seriesRepo.RescheduleSeries(ctx, series, upcoming)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		return nil, ErrSlotTaken
+	}
+	if err != nil {
+		return nil, errors.New("failed to modify reservation series")
+	}
+
+	return s.GetSeries(ctx, userID, id)
+}
+
+// checkMove checks one occurrence's new range against its court's schedule and against
+// bookings outside the series; the series' own occurrences are moving too.
+func (s *reservationSeriesService) checkMove(ctx context.Context, series *models.ReservationSeries, occurrence *models.Reservation, start, end int) error {
+	schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, &occurrence.Court, occurrence.ReservationDate)
+	if err != nil {
+		return err
+	}
+	if err := schedule.Validate(start, end); err != nil {
+		return err
+	}
+
+	booked, err := s.reservationRepo.GetReservationsByDateAndCourt(ctx, occurrence.ReservationDate, occurrence.CourtID)
+	if err != nil {
+		return errors.New("failed to check availability")
+	}
+	for _, other := range booked {
+		if other.SeriesID != nil && *other.SeriesID == series.ID {
+			continue
+		}
+		otherStart, otherEnd, err := utils.ParseTimeSlot(other.TimeSlot)
+		if err != nil {
+			continue
+		}
+		if utils.RangesOverlap(start, end, otherStart, otherEnd) {
+			return ErrSlotTaken
+		}
+	}
+	return nil
+}
+
+// expirePendingPayment closes the series' pending order after it was cancelled. Failing
+// to do so is only logged: the cancellation stands, and a late payment is refunded.
+func (s *reservationSeriesService) expirePendingPayment(ctx context.Context, seriesID uint) {
+	payment, err := s.paymentRepo.GetActivePaymentBySeriesID(ctx, seriesID)
+	if err != nil || payment.Status != "pending" {
+		return
+	}
+	if err := s.midtransService.ExpirePayment(ctx, payment); err != nil {
+		fmt.Printf("ERROR expiring payment %s of cancelled series %d: %v\n", payment.MidtransOrderID, seriesID, err)
+	}
+}
+
+// CancelSeries cancels every upcoming occurrence of the series. A paid series is refunded
+// with the cancellation policy applied to each occurrence separately, in one refund; the
+// order of an unpaid one is expired so it can no longer be paid.
+func (s *reservationSeriesService) CancelSeries(ctx context.Context, userID uint, id uint) (*models.Refund, error) {
+	series, err := s.loadSeries(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if series.Status == "cancelled" {
+		return nil, errors.New("reservation series is already cancelled")
+	}
+
+	now := time.Now()
+	upcoming := upcomingOccurrences(series, now)
+
+	ids := make([]uint, 0, len(upcoming))
+	for _, occurrence := range upcoming {
+		ids = append(ids, occurrence.ID)
+	}
+
+	// Cancel before refunding, so only the request that actually cancelled an occurrence
+	// refunds it
+	confirmedIDs, err := s.seriesRepo.CancelSeries(ctx, series.ID, ids)
+	if errors.Is(err, repositories.ErrSeriesCancelled) {
+		return nil, errors.New("reservation series is already cancelled")
+	}
+	if err != nil {
+		return nil, errors.New("failed to cancel reservation series")
+	}
+
+	// Money arriving for it anyway is refunded when the payment settles
+	s.expirePendingPayment(ctx, series.ID)
+
+	var amount money.Amount
+	for _, occurrence := range upcoming {
+		if slices.Contains(confirmedIDs, occurrence.ID) {
+			amount += refundAmountFor(occurrence.Court, occurrence.TotalAmount, occurrence.StartAt, now)
+		}
+	}
+	if amount <= 0 {
+		return nil, nil
+	}
+
+	payment, err := s.paymentRepo.GetPaidPaymentBySeriesID(ctx, series.ID)
+	if err != nil {
+		return nil, errors.New("reservation series was cancelled but its paid payment was not found")
+	}
+	refund, err := s.midtransService.RefundPayment(ctx, payment, amount, "Reservation series cancelled by customer")
+	if err != nil {
+		return refund, errors.New("reservation series was cancelled but refunding the payment failed")
+	}
+
+	return refund, nil
+}
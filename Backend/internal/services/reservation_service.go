@@ -3,113 +3,238 @@ package services
 import (
 	"backend/internal/models"
 	"backend/internal/repositories"
+	"backend/pkg/money"
+	"backend/pkg/utils"
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
+var (
+	// ErrSlotTaken is returned when the requested range overlaps an active reservation.
+	ErrSlotTaken = repositories.ErrSlotTaken
+	// ErrRescheduleCutoff is returned when a booking starts too soon to be moved by the customer.
+	ErrRescheduleCutoff = errors.New("reservation can no longer be rescheduled")
+	// ErrReservationChanged is returned when another request changed the reservation first.
+	ErrReservationChanged = repositories.ErrReservationChanged
+)
+
 type ReservationService interface {
 	CreateReservation(ctx context.Context, userID uint, req *models.CreateReservationRequest) (*models.ReservationResponse, error)
 	GetUserReservations(ctx context.Context, userID uint) ([]models.ReservationResponse, error)
 	GetReservationByID(ctx context.Context, reservationID uint, userID uint) (*models.ReservationResponse, error)
-	CancelReservation(ctx context.Context, reservationID uint, userID uint) error
+	CancelReservation(ctx context.Context, reservationID uint, userID uint) ([]models.Refund, error)
+	RescheduleReservation(ctx context.Context, reservationID uint, userID uint, req *models.RescheduleReservationRequest) (*models.ReservationResponse, []models.Refund, error)
+	GetNoShowStatus(ctx context.Context, userID uint) (*models.NoShowStatus, error)
+	ExpireStaleHolds(ctx context.Context) (int64, error)
 }
 
 type reservationService struct {
 	reservationRepo repositories.ReservationRepository
 	courtRepo       repositories.CourtRepository
+	closureRepo     repositories.ClosureRepository
+	paymentRepo     repositories.PaymentRepository
+	midtransService MidtransService
+	pricingService  PricingService
+	waitlistService WaitlistService
+	noShowPolicy    NoShowPolicy
+	holdTTL         time.Duration
 }
 
 func NewReservationService(
 	reservationRepo repositories.ReservationRepository,
 	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	paymentRepo repositories.PaymentRepository,
+	midtransService MidtransService,
+	pricingService PricingService,
+	waitlistService WaitlistService,
+	noShowPolicy NoShowPolicy,
+	holdTTL time.Duration,
 ) ReservationService {
 	return &reservationService{
 		reservationRepo: reservationRepo,
 		courtRepo:       courtRepo,
+		closureRepo:     closureRepo,
+		paymentRepo:     paymentRepo,
+		midtransService: midtransService,
+		pricingService:  pricingService,
+		waitlistService: waitlistService,
+		noShowPolicy:    noShowPolicy,
+		holdTTL:         holdTTL,
+	}
+}
+
+func toReservationResponse(reservation *models.Reservation) models.ReservationResponse {
+	return models.ReservationResponse{
+		ID:              reservation.ID,
+		UserID:          reservation.UserID,
+		CourtID:         reservation.CourtID,
+		CourtName:       reservation.Court.Name,
+		ReservationDate: reservation.ReservationDate.Format("2006-01-02"),
+		TimeSlot:        reservation.TimeSlot,
+		DurationHours:   reservation.DurationHours,
+		DurationMinutes: reservation.DurationMinutes,
+		TotalAmount:     reservation.TotalAmount,
+		AmountDue:       reservation.AmountDue,
+		Status:          reservation.Status,
+		HoldExpiresAt:   reservation.HoldExpiresAt,
+		ClosureID:       reservation.ClosureID,
+		SeriesID:        reservation.SeriesID,
+		SplitDeadline:   reservation.SplitDeadline,
+		CheckedInAt:     reservation.CheckedInAt,
+		CreatedAt:       reservation.CreatedAt,
+		PriceBreakdown:  reservation.PriceLines,
+	}
+}
+
+// resolveTimeSlot normalizes the requested booking window into start and end minutes.
+func resolveTimeSlot(req *models.CreateReservationRequest) (int, int, error) {
+	switch {
+	case req.StartTime != "" && req.EndTime != "":
+		return utils.ParseTimeSlot(req.StartTime + "-" + req.EndTime)
+	case req.StartTime != "" && (req.DurationMinutes > 0 || req.DurationHours > 0):
+		start, err := utils.ParseClock(req.StartTime)
+		if err != nil {
+			return 0, 0, err
+		}
+		duration := req.DurationMinutes
+		if duration == 0 {
+			duration = req.DurationHours * 60
+		}
+		end := start + duration
+		if end > utils.MinutesPerDay {
+			return 0, 0, errors.New("reservation cannot extend past midnight")
+		}
+		return start, end, nil
+	case req.TimeSlot != "":
+		return utils.ParseTimeSlot(req.TimeSlot)
+	default:
+		return 0, 0, errors.New("provide time_slot, start_time and end_time, or start_time and duration_minutes")
 	}
 }
 
-func calculateDuration(timeSlot string) int {
-	return 1 // Default 1 hour for now
+// slotTime converts a booking date and minutes since midnight into a timestamp in loc,
+// the timezone of the court's venue.
+func slotTime(date time.Time, minutes int, loc *time.Location) time.Time {
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc).Add(time.Duration(minutes) * time.Minute)
 }
 
 func (s *reservationService) CreateReservation(ctx context.Context, userID uint, req *models.CreateReservationRequest) (*models.ReservationResponse, error) {
-	// Parse date
-	parsedDate, err := time.Parse("2006-01-02", req.Date)
+	if err := s.noShowPolicy.Check(ctx, s.reservationRepo, userID); err != nil {
+		return nil, err
+	}
+
+	reservation, err := quoteReservation(ctx, s.courtRepo, s.closureRepo, s.reservationRepo, s.pricingService, req)
 	if err != nil {
-		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+		return nil, err
 	}
 
-	// Check if court exists
-	_, err = s.courtRepo.GetCourtByID(ctx, req.CourtID)
+	// Hold the slot until payment completes or the hold lapses
+	holdExpiresAt := time.Now().Add(s.holdTTL)
+	reservation.UserID = userID
+	reservation.Status = "pending" // Will be confirmed after payment
+	reservation.HoldExpiresAt = &holdExpiresAt
+
+	err = s.reservationRepo.CreateReservation(ctx, reservation, nil)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		return nil, ErrSlotTaken
+	}
 	if err != nil {
-		return nil, errors.New("court not found")
+		return nil, errors.New("failed to create reservation")
 	}
 
-	// Check court availability
-	isAvailable, err := s.reservationRepo.CheckExistingReservation(ctx, parsedDate, req.TimeSlot, req.CourtID)
+	// Get the created reservation with relationships
+	createdReservation, err := s.reservationRepo.GetReservationByID(ctx, reservation.ID)
 	if err != nil {
-		return nil, errors.New("failed to check availability")
+		return nil, errors.New("failed to fetch created reservation")
 	}
-	if isAvailable {
-		return nil, errors.New("selected timeslot is already booked")
+
+	reservationResponse := toReservationResponse(createdReservation)
+	return &reservationResponse, nil
+}
+
+// quoteReservation checks the requested range against the court's schedule and existing
+// bookings and prices it. The returned reservation has no owner or status and is not saved.
+func quoteReservation(
+	ctx context.Context,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	reservationRepo repositories.ReservationRepository,
+	pricingService PricingService,
+	req *models.CreateReservationRequest,
+) (*models.Reservation, error) {
+	return quoteReservationExcept(ctx, courtRepo, closureRepo, reservationRepo, pricingService, req, 0)
+}
+
+// quoteReservationExcept is quoteReservation ignoring the reservation excludeID when
+// checking availability, so a booking can be re-quoted over its own slot.
+func quoteReservationExcept(
+	ctx context.Context,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	reservationRepo repositories.ReservationRepository,
+	pricingService PricingService,
+	req *models.CreateReservationRequest,
+	excludeID uint,
+) (*models.Reservation, error) {
+	// Parse date
+	parsedDate, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
 	}
 
-	// Validate timeslot format (basic validation)
-	if len(req.TimeSlot) != 11 || req.TimeSlot[5] != '-' {
-		return nil, errors.New("invalid timeslot format. Use HH:MM-HH:MM")
+	// Resolve and validate the requested time range
+	start, end, err := resolveTimeSlot(req)
+	if err != nil {
+		return nil, err
 	}
+	timeSlot := utils.FormatTimeSlot(start, end)
 
 	// Get court details including PRICE
-	court, err := s.courtRepo.GetCourtByID(ctx, req.CourtID)
+	court, err := courtRepo.GetCourtByID(ctx, req.CourtID)
 	if err != nil {
 		return nil, errors.New("court not found")
 	}
 
-	// CALCULATE DURATION from time slot
-	duration := calculateDuration(req.TimeSlot) // Function baru
-
-	// CALCULATE TOTAL AMOUNT
-	totalAmount := court.PricePerHour * float64(duration)
-
-	// Create reservation
-	reservation := &models.Reservation{
-		UserID:          userID,
-		CourtID:         req.CourtID,
-		ReservationDate: parsedDate,
-		TimeSlot:        req.TimeSlot,
-		DurationHours:   duration,    // NEW
-		TotalAmount:     totalAmount, // NEW
-		Status:          "pending",   // Will be confirmed after payment
-	}
-
-	err = s.reservationRepo.CreateReservation(ctx, reservation)
+	// The range must fit the court's opening hours and slot length, and avoid closures
+	schedule, err := loadDaySchedule(ctx, courtRepo, closureRepo, court, parsedDate)
 	if err != nil {
-		return nil, errors.New("failed to create reservation")
+		return nil, err
 	}
+	if err := schedule.Validate(start, end); err != nil {
+		return nil, err
+	}
+	duration := end - start
 
-	// Get the created reservation with relationships
-	createdReservation, err := s.reservationRepo.GetReservationByID(ctx, reservation.ID)
+	// Check court availability (any overlapping booking blocks the range)
+	isBooked, err := reservationRepo.CheckExistingReservationExcept(ctx, parsedDate, timeSlot, req.CourtID, excludeID)
 	if err != nil {
-		return nil, errors.New("failed to fetch created reservation")
+		return nil, errors.New("failed to check availability")
+	}
+	if isBooked {
+		return nil, ErrSlotTaken
 	}
 
-	// Convert to response - ✅ TAMBAHKAN DURATION_HOURS & TOTAL_AMOUNT
-	reservationResponse := &models.ReservationResponse{
-		ID:              createdReservation.ID,
-		UserID:          createdReservation.UserID,
-		CourtID:         createdReservation.CourtID,
-		CourtName:       createdReservation.Court.Name,
-		ReservationDate: createdReservation.ReservationDate.Format("2006-01-02"),
-		TimeSlot:        createdReservation.TimeSlot,
-		DurationHours:   createdReservation.DurationHours, // ✅ NEW
-		TotalAmount:     createdReservation.TotalAmount,   // ✅ NEW
-		Status:          createdReservation.Status,
-		CreatedAt:       createdReservation.CreatedAt,
+	// Price the range with the court's pricing rules (peak hours, weekends, holidays)
+	quote, err := pricingService.Quote(ctx, court, parsedDate, start, end)
+	if err != nil {
+		return nil, err
 	}
 
-	return reservationResponse, nil
+	return &models.Reservation{
+		CourtID:         req.CourtID,
+		ReservationDate: parsedDate,
+		TimeSlot:        timeSlot,
+		StartAt:         slotTime(parsedDate, start, courtLocation(court)),
+		EndAt:           slotTime(parsedDate, end, courtLocation(court)),
+		DurationHours:   duration / 60, // NEW
+		DurationMinutes: duration,
+		TotalAmount:     quote.Total, // NEW
+		PriceLines:      quote.Lines,
+	}, nil
 }
 
 func (s *reservationService) GetUserReservations(ctx context.Context, userID uint) ([]models.ReservationResponse, error) {
@@ -119,20 +244,8 @@ func (s *reservationService) GetUserReservations(ctx context.Context, userID uin
 	}
 
 	var reservationResponses []models.ReservationResponse
-	for _, reservation := range reservations {
-		reservationResponse := models.ReservationResponse{
-			ID:              reservation.ID,
-			UserID:          reservation.UserID,
-			CourtID:         reservation.CourtID,
-			CourtName:       reservation.Court.Name,
-			ReservationDate: reservation.ReservationDate.Format("2006-01-02"),
-			TimeSlot:        reservation.TimeSlot,
-			DurationHours:   reservation.DurationHours, // ✅ NEW
-			TotalAmount:     reservation.TotalAmount,   // ✅ NEW
-			Status:          reservation.Status,
-			CreatedAt:       reservation.CreatedAt,
-		}
-		reservationResponses = append(reservationResponses, reservationResponse)
+	for i := range reservations {
+		reservationResponses = append(reservationResponses, toReservationResponse(&reservations[i]))
 	}
 
 	return reservationResponses, nil
@@ -149,44 +262,204 @@ func (s *reservationService) GetReservationByID(ctx context.Context, reservation
 		return nil, errors.New("unauthorized to access this reservation")
 	}
 
-	reservationResponse := &models.ReservationResponse{
-		ID:              reservation.ID,
-		UserID:          reservation.UserID,
-		CourtID:         reservation.CourtID,
-		CourtName:       reservation.Court.Name,
-		ReservationDate: reservation.ReservationDate.Format("2006-01-02"),
-		TimeSlot:        reservation.TimeSlot,
-		DurationHours:   reservation.DurationHours, // ✅ NEW
-		TotalAmount:     reservation.TotalAmount,   // ✅ NEW
-		Status:          reservation.Status,
-		CreatedAt:       reservation.CreatedAt,
-	}
-
-	return reservationResponse, nil
+	reservationResponse := toReservationResponse(reservation)
+	return &reservationResponse, nil
 }
 
-func (s *reservationService) CancelReservation(ctx context.Context, reservationID uint, userID uint) error {
+// CancelReservation cancels a pending reservation, or a confirmed one with a refund
+// according to the court's cancellation policy. The slot is freed afterwards.
+func (s *reservationService) CancelReservation(ctx context.Context, reservationID uint, userID uint) ([]models.Refund, error) {
 	// First get the reservation to check ownership
 	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
 	if err != nil {
-		return errors.New("reservation not found")
+		return nil, errors.New("reservation not found")
 	}
 
 	// Check if reservation belongs to user
 	if reservation.UserID != userID {
-		return errors.New("unauthorized to cancel this reservation")
+		return nil, errors.New("unauthorized to cancel this reservation")
+	}
+
+	now := time.Now()
+	switch reservation.Status {
+	case "pending":
+		// Nothing paid yet, just release the hold
+	case "confirmed":
+		if !reservation.StartAt.After(now) {
+			return nil, errors.New("reservation has already started")
+		}
+	default:
+		return nil, errors.New("only pending or confirmed reservations can be cancelled")
+	}
+
+	// Cancel before refunding, so only the request that actually cancelled it refunds
+	cancelled, err := s.reservationRepo.TransitionReservationStatus(ctx, reservationID, reservation.Status, "cancelled", nil)
+	if err != nil {
+		return nil, errors.New("failed to cancel reservation")
+	}
+	if !cancelled {
+		return nil, errors.New("reservation was changed by another request, try again")
+	}
+	expirePendingPayment(ctx, s.paymentRepo, s.midtransService, reservation)
+
+	var refunds []models.Refund
+	if reservation.Status == "confirmed" {
+		shares, err := refundSharesFor(ctx, s.paymentRepo, reservation)
+		if err != nil {
+			return nil, errors.New("reservation was cancelled but its paid payment was not found")
+		}
+
+		amount := refundAmountFor(reservation.Court, sharesTotal(shares), reservation.StartAt, now)
+		if amount > 0 {
+			refunds, err = refundShares(ctx, s.midtransService, shares, amount, "Reservation cancelled by customer")
+			if err != nil {
+				return refunds, errors.New("reservation was cancelled but refunding the payment failed")
+			}
+		}
+	}
+
+	// Shares already paid towards a split booking go back to the players. This runs after
+	// the status change, so a share settling meanwhile is refunded when it arrives instead.
+	if reservation.Status == "pending" {
+		refunds, err = refundPaidShares(ctx, s.paymentRepo, s.midtransService, reservation, "Split reservation cancelled by organiser")
+		if err != nil {
+			return refunds, errors.New("reservation was cancelled but refunding paid shares failed")
+		}
+	}
+
+	// Offer the freed slot to the next user on the waitlist
+	s.waitlistService.OfferFreedSlot(ctx, reservation.CourtID, reservation.ReservationDate)
+
+	return refunds, nil
+}
+
+// RescheduleReservation moves the user's booking to another slot, and optionally another
+// court, until the court's reschedule cutoff. The booking is re-priced: a confirmed booking
+// that got cheaper is partly refunded, one that got pricier owes the difference as a top-up
+// payment. The old slot is offered to the waitlist afterwards.
+func (s *reservationService) RescheduleReservation(ctx context.Context, reservationID uint, userID uint, req *models.RescheduleReservationRequest) (*models.ReservationResponse, []models.Refund, error) {
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
+	if err != nil {
+		return nil, nil, ErrReservationNotFound
+	}
+	if reservation.UserID != userID {
+		return nil, nil, ErrNotReservationOwner
+	}
+
+	switch {
+	case reservation.Status != "pending" && reservation.Status != "confirmed":
+		return nil, nil, errors.New("only pending or confirmed reservations can be rescheduled")
+	case reservation.SeriesID != nil:
+		return nil, nil, fmt.Errorf("reservation is part of series %d, modify the series instead", *reservation.SeriesID)
+	case reservation.Status == "pending" && reservation.SplitDeadline != nil:
+		return nil, nil, errors.New("reservation is being paid in shares, it can be rescheduled once confirmed")
+	case reservation.AmountDue > 0:
+		return nil, nil, fmt.Errorf("pay the outstanding top-up of %s before rescheduling again", reservation.AmountDue)
+	}
+
+	now := time.Now()
+	cutoff := reservation.Court.RescheduleCutoffHours
+	if reservation.StartAt.Sub(now) < time.Duration(cutoff)*time.Hour {
+		return nil, nil, fmt.Errorf("%w: changes close %d hours before start", ErrRescheduleCutoff, cutoff)
+	}
+
+	// A start time alone keeps the current duration
+	quoteReq := &models.CreateReservationRequest{
+		CourtID:         valueOr(req.CourtID, reservation.CourtID),
+		Date:            req.Date,
+		TimeSlot:        req.TimeSlot,
+		StartTime:       req.StartTime,
+		EndTime:         req.EndTime,
+		DurationMinutes: req.DurationMinutes,
+	}
+	if quoteReq.StartTime != "" && quoteReq.EndTime == "" && quoteReq.DurationMinutes == 0 {
+		quoteReq.DurationMinutes = reservation.DurationMinutes
+	}
+
+	quote, err := quoteReservationExcept(ctx, s.courtRepo, s.closureRepo, s.reservationRepo, s.pricingService, quoteReq, reservation.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !quote.StartAt.After(now) {
+		return nil, nil, errors.New("new time has already started")
+	}
+
+	// A pending booking is simply re-priced, unless a payment for the old price is under way.
+	// A confirmed booking settles the difference against what was paid.
+	var shares []refundShare
+	var diff money.Amount
+	switch reservation.Status {
+	case "pending":
+		if existing, err := s.paymentRepo.GetActivePaymentByReservationID(ctx, reservation.ID); err == nil && existing.Status == "pending" {
+			return nil, nil, fmt.Errorf("%w: cancel it or let it expire before rescheduling", ErrPaymentInProgress)
+		}
+	case "confirmed":
+		shares, err = refundSharesFor(ctx, s.paymentRepo, reservation)
+		if err != nil {
+			return nil, nil, errors.New("paid payment not found for reservation")
+		}
+		diff = quote.TotalAmount - sharesTotal(shares)
+		if diff != 0 {
+			for _, share := range shares {
+				if share.payment.PaymentMethod == "cash" {
+					return nil, nil, errors.New("bookings paid in cash can only move to a slot with the same price, ask the front desk")
+				}
+			}
+		}
+	}
+
+	before := *reservation
+	oldCourtID, oldDate := reservation.CourtID, reservation.ReservationDate
+	reservation.CourtID = quote.CourtID
+	reservation.ReservationDate = quote.ReservationDate
+	reservation.TimeSlot = quote.TimeSlot
+	reservation.StartAt = quote.StartAt
+	reservation.EndAt = quote.EndAt
+	reservation.DurationHours = quote.DurationHours
+	reservation.DurationMinutes = quote.DurationMinutes
+	reservation.TotalAmount = quote.TotalAmount
+	reservation.PriceLines = quote.PriceLines
+	reservation.AmountDue = max(diff, 0)
+
+	// Only applied if nothing cancelled or re-priced the booking since it was read above
+	err = s.reservationRepo.RescheduleReservation(ctx, reservation, &before)
+	if errors.Is(err, repositories.ErrSlotTaken) {
+		return nil, nil, ErrSlotTaken
+	}
+	if errors.Is(err, ErrReservationChanged) {
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, errors.New("failed to reschedule reservation")
 	}
 
-	// Check if reservation can be cancelled (only pending reservations)
-	if reservation.Status != "pending" {
-		return errors.New("only pending reservations can be cancelled")
+	var refunds []models.Refund
+	if diff < 0 {
+		refunds, err = refundShares(ctx, s.midtransService, shares, -diff, "Reservation rescheduled to a cheaper slot")
+		if err != nil {
+			fmt.Printf("❌ Refund after rescheduling reservation %d failed: %v\n", reservation.ID, err)
+			return nil, refunds, fmt.Errorf("%w: reservation was rescheduled but %s could not be refunded", ErrRefundFailed, -diff)
+		}
 	}
 
-	// Update reservation status to cancelled
-	err = s.reservationRepo.UpdateReservationStatus(ctx, reservationID, "cancelled")
+	// Offer the freed slot to the next user on the waitlist
+	s.waitlistService.OfferFreedSlot(ctx, oldCourtID, oldDate)
+
+	rescheduled, err := s.reservationRepo.GetReservationByID(ctx, reservation.ID)
 	if err != nil {
-		return errors.New("failed to cancel reservation")
+		return nil, refunds, errors.New("failed to fetch rescheduled reservation")
 	}
 
-	return nil
+	reservationResponse := toReservationResponse(rescheduled)
+	return &reservationResponse, refunds, nil
+}
+
+// GetNoShowStatus reports the user's recent no-shows and whether they block booking.
+func (s *reservationService) GetNoShowStatus(ctx context.Context, userID uint) (*models.NoShowStatus, error) {
+	return s.noShowPolicy.Status(ctx, s.reservationRepo, userID)
+}
+
+// ExpireStaleHolds releases pending reservations whose hold window has passed.
+func (s *reservationService) ExpireStaleHolds(ctx context.Context) (int64, error) {
+	return s.reservationRepo.ExpireStaleHolds(ctx, time.Now())
 }
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/money"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotSplit is returned when asking for the split of a reservation paid by its organiser alone.
+	ErrNotSplit = errors.New("reservation is not split between players")
+	// ErrAlreadySplit is returned when splitting a reservation a second time.
+	ErrAlreadySplit = errors.New("reservation is already split between players")
+)
+
+// SplitPaymentService lets the organiser of a pending reservation share its court fee with
+// the other players. Every player pays an equal share through their own payment until the
+// deadline; after it the organiser pays whatever is left. The reservation is confirmed
+// once every share is paid or covered, and its hold lasts until then.
+type SplitPaymentService interface {
+	SplitReservation(ctx context.Context, userID uint, reservationID uint, req *models.SplitReservationRequest) (*models.SplitPaymentResponse, error)
+	GetSplit(ctx context.Context, userID uint, reservationID uint) (*models.SplitPaymentResponse, error)
+	GetUserSplits(ctx context.Context, userID uint) ([]models.SplitPaymentResponse, error)
+	RefundExpiredSplits(ctx context.Context) (int, error)
+}
+
+type splitPaymentService struct {
+	reservationRepo repositories.ReservationRepository
+	participantRepo repositories.ParticipantRepository
+	userRepo        repositories.UserRepository
+	paymentRepo     repositories.PaymentRepository
+	midtransService MidtransService
+	splitWindow     time.Duration
+	holdTTL         time.Duration
+}
+
+func NewSplitPaymentService(
+	reservationRepo repositories.ReservationRepository,
+	participantRepo repositories.ParticipantRepository,
+	userRepo repositories.UserRepository,
+	paymentRepo repositories.PaymentRepository,
+	midtransService MidtransService,
+	splitWindow time.Duration,
+	holdTTL time.Duration,
+) SplitPaymentService {
+	return &splitPaymentService{
+		reservationRepo: reservationRepo,
+		participantRepo: participantRepo,
+		userRepo:        userRepo,
+		paymentRepo:     paymentRepo,
+		midtransService: midtransService,
+		splitWindow:     splitWindow,
+		holdTTL:         holdTTL,
+	}
+}
+
+func toSplitPaymentResponse(reservation *models.Reservation, participants []models.ReservationParticipant) models.SplitPaymentResponse {
+	response := models.SplitPaymentResponse{
+		ReservationID:     reservation.ID,
+		OrganiserID:       reservation.UserID,
+		CourtName:         reservation.Court.Name,
+		ReservationDate:   reservation.ReservationDate.Format("2006-01-02"),
+		TimeSlot:          reservation.TimeSlot,
+		ReservationStatus: reservation.Status,
+		TotalAmount:       reservation.TotalAmount,
+		Participants:      make([]models.ParticipantResponse, 0, len(participants)),
+	}
+	if reservation.SplitDeadline != nil {
+		response.Deadline = *reservation.SplitDeadline
+	}
+
+	for _, participant := range participants {
+		if participant.Status != "pending" {
+			response.PaidAmount += participant.ShareAmount
+		}
+		response.Participants = append(response.Participants, models.ParticipantResponse{
+			ID:          participant.ID,
+			UserID:      participant.UserID,
+			Name:        participant.User.Name,
+			Email:       participant.User.Email,
+			Organiser:   participant.UserID == reservation.UserID,
+			ShareAmount: participant.ShareAmount,
+			Status:      participant.Status,
+			PaidAt:      participant.PaidAt,
+		})
+	}
+	response.Remaining = response.TotalAmount - response.PaidAmount
+	return response
+}
+
+// SplitReservation invites players to share the organiser's pending reservation. The fee is
+// divided equally, with any rupiah left over added to the organiser's share. The deadline
+// defaults to the split window from now and must leave the organiser one hold period
+// before the start to cover unpaid shares.
+func (s *splitPaymentService) SplitReservation(ctx context.Context, userID uint, reservationID uint, req *models.SplitReservationRequest) (*models.SplitPaymentResponse, error) {
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.UserID != userID {
+		return nil, ErrNotReservationOwner
+	}
+
+	now := time.Now()
+	switch {
+	case reservation.SplitDeadline != nil:
+		return nil, ErrAlreadySplit
+	case reservation.Status != "pending":
+		return nil, fmt.Errorf("%w: only unpaid reservations can be split", ErrReservationNotPayable)
+	case reservation.HoldExpiresAt != nil && !reservation.HoldExpiresAt.After(now):
+		return nil, fmt.Errorf("%w: reservation hold has expired", ErrReservationNotPayable)
+	case reservation.SeriesID != nil:
+		return nil, fmt.Errorf("%w: reservation is part of series %d", ErrReservationNotPayable, *reservation.SeriesID)
+	}
+	if existing, err := s.paymentRepo.GetActivePaymentByReservationID(ctx, reservation.ID); err == nil && existing.Status == "pending" {
+		return nil, fmt.Errorf("%w: cancel it or let it expire before splitting", ErrPaymentInProgress)
+	}
+
+	latest := reservation.StartAt.Add(-s.holdTTL)
+	deadline := now.Add(s.splitWindow)
+	if req.Deadline != nil {
+		deadline = *req.Deadline
+		if deadline.After(latest) {
+			return nil, fmt.Errorf("deadline must be at least %s before the reservation starts", s.holdTTL)
+		}
+	} else if deadline.After(latest) {
+		deadline = latest
+	}
+	if !deadline.After(now) {
+		return nil, errors.New("reservation starts too soon to split the payment")
+	}
+
+	players, err := s.resolvePlayers(ctx, userID, req.Participants)
+	if err != nil {
+		return nil, err
+	}
+
+	// Equal shares; the organiser's share takes the rupiah that do not divide evenly
+	count := money.Amount(len(players) + 1)
+	share := reservation.TotalAmount / count
+	participants := []models.ReservationParticipant{{
+		UserID:      userID,
+		ShareAmount: reservation.TotalAmount - share*(count-1),
+	}}
+	for _, player := range players {
+		participants = append(participants, models.ReservationParticipant{
+			UserID:      player.ID,
+			ShareAmount: share,
+		})
+	}
+
+	err = s.participantRepo.CreateSplit(ctx, reservation.ID, deadline, deadline.Add(s.holdTTL), participants)
+	if errors.Is(err, repositories.ErrSplitClosed) {
+		return nil, fmt.Errorf("%w: reservation changed, try again", ErrReservationNotPayable)
+	}
+	if err != nil {
+		return nil, errors.New("failed to split reservation")
+	}
+
+	return s.GetSplit(ctx, userID, reservation.ID)
+}
+
+// resolvePlayers looks up the invited players, who must have an account to pay their share.
+func (s *splitPaymentService) resolvePlayers(ctx context.Context, organiserID uint, invites []models.ParticipantInvite) ([]models.User, error) {
+	seen := map[uint]bool{organiserID: true}
+	players := make([]models.User, 0, len(invites))
+
+	for _, invite := range invites {
+		var player *models.User
+		var err error
+		switch {
+		case invite.UserID != 0:
+			player, err = s.userRepo.GetUserByID(ctx, invite.UserID)
+			if err != nil {
+				return nil, fmt.Errorf("no player with user_id %d", invite.UserID)
+			}
+		case invite.Email != "":
+			player, err = s.userRepo.GetUserByEmail(ctx, invite.Email)
+			if err != nil {
+				return nil, fmt.Errorf("no player registered with %s", invite.Email)
+			}
+		default:
+			return nil, errors.New("each participant needs a user_id or email")
+		}
+
+		if player.ID == organiserID {
+			return nil, errors.New("the organiser is part of the split already")
+		}
+		if seen[player.ID] {
+			return nil, fmt.Errorf("%s is invited more than once", player.Email)
+		}
+		seen[player.ID] = true
+		players = append(players, *player)
+	}
+	return players, nil
+}
+
+// GetSplit returns the payment progress of a split reservation to its organiser or players.
+func (s *splitPaymentService) GetSplit(ctx context.Context, userID uint, reservationID uint) (*models.SplitPaymentResponse, error) {
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, reservationID)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	if reservation.UserID != userID {
+		_, err := s.participantRepo.GetParticipant(ctx, reservation.ID, userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotParticipant
+		}
+		if err != nil {
+			return nil, errors.New("failed to get participants")
+		}
+	}
+	if reservation.SplitDeadline == nil {
+		return nil, ErrNotSplit
+	}
+
+	participants, err := s.participantRepo.GetParticipants(ctx, reservation.ID)
+	if err != nil {
+		return nil, errors.New("failed to get participants")
+	}
+
+	response := toSplitPaymentResponse(reservation, participants)
+	return &response, nil
+}
+
+// GetUserSplits lists the split reservations the user organises or was invited to.
+func (s *splitPaymentService) GetUserSplits(ctx context.Context, userID uint) ([]models.SplitPaymentResponse, error) {
+	participations, err := s.participantRepo.GetUserParticipations(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get split reservations")
+	}
+
+	responses := make([]models.SplitPaymentResponse, 0, len(participations))
+	for i := range participations {
+		reservation := &participations[i].Reservation
+		participants, err := s.participantRepo.GetParticipants(ctx, reservation.ID)
+		if err != nil {
+			return nil, errors.New("failed to get participants")
+		}
+		responses = append(responses, toSplitPaymentResponse(reservation, participants))
+	}
+	return responses, nil
+}
+
+// RefundExpiredSplits refunds the shares paid towards split reservations whose hold lapsed
+// before every share was paid. It returns how many payments were refunded.
+func (s *splitPaymentService) RefundExpiredSplits(ctx context.Context) (int, error) {
+	payments, err := s.paymentRepo.GetPaidSplitPaymentsOfExpiredReservations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	refunded := 0
+	for _, share := range refundableShares(payments) {
+		if _, err := s.midtransService.RefundPayment(ctx, share.payment, share.amount, "Split reservation expired before it was fully paid"); err != nil {
+			fmt.Printf("❌ Refunding share OrderID %s failed: %v\n", share.payment.MidtransOrderID, err)
+			continue
+		}
+		refunded++
+	}
+	return refunded, nil
+}
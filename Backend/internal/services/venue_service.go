@@ -0,0 +1,224 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrVenueNotFound is returned when the venue does not exist.
+	ErrVenueNotFound = errors.New("venue not found")
+	// ErrOtherVenue is returned when staff act on a court or booking of another venue.
+	ErrOtherVenue = errors.New("this belongs to another venue")
+)
+
+// Defaults for a new venue.
+const defaultVenueTimezone = "Asia/Jakarta"
+
+type VenueService interface {
+	GetVenues(ctx context.Context) ([]models.Venue, error)
+	GetVenueByID(ctx context.Context, id uint) (*models.Venue, error)
+	ListVenues(ctx context.Context) ([]models.Venue, error)
+	CreateVenue(ctx context.Context, actorID uint, req *models.CreateVenueRequest) (*models.Venue, error)
+	UpdateVenue(ctx context.Context, actorID uint, id uint, req *models.UpdateVenueRequest) (*models.Venue, error)
+	SetUserRole(ctx context.Context, actorID uint, userID uint, req *models.SetUserRoleRequest) (*models.UserResponse, error)
+}
+
+type venueService struct {
+	venueRepo repositories.VenueRepository
+	userRepo  repositories.UserRepository
+}
+
+// NewVenueService creates the venue service. Changes are audited in the same transaction
+// by the repositories.
+func NewVenueService(
+	venueRepo repositories.VenueRepository,
+	userRepo repositories.UserRepository,
+) VenueService {
+	return &venueService{
+		venueRepo: venueRepo,
+		userRepo:  userRepo,
+	}
+}
+
+func (s *venueService) GetVenues(ctx context.Context) ([]models.Venue, error) {
+	venues, err := s.venueRepo.GetActiveVenues(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get venues")
+	}
+	return venues, nil
+}
+
+func (s *venueService) GetVenueByID(ctx context.Context, id uint) (*models.Venue, error) {
+	venue, err := s.venueRepo.GetVenueByID(ctx, id)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	return venue, nil
+}
+
+func (s *venueService) ListVenues(ctx context.Context) ([]models.Venue, error) {
+	venues, err := s.venueRepo.ListVenues(ctx)
+	if err != nil {
+		return nil, errors.New("failed to get venues")
+	}
+	return venues, nil
+}
+
+func (s *venueService) CreateVenue(ctx context.Context, actorID uint, req *models.CreateVenueRequest) (*models.Venue, error) {
+	venue := &models.Venue{
+		Name:      req.Name,
+		Address:   req.Address,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Timezone:  valueOr(req.Timezone, defaultVenueTimezone),
+		Phone:     req.Phone,
+		Email:     req.Email,
+		Status:    "active",
+		OpenTime:  valueOr(req.OpenTime, defaultOpenTime),
+		CloseTime: valueOr(req.CloseTime, defaultCloseTime),
+	}
+	if err := validateVenue(venue); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "venue.create", "venue", venue.ID, nil, venue)
+	}
+	if err := s.venueRepo.CreateVenue(ctx, venue, audit); err != nil {
+		return nil, fmt.Errorf("failed to create venue: %v", err)
+	}
+	return venue, nil
+}
+
+func (s *venueService) UpdateVenue(ctx context.Context, actorID uint, id uint, req *models.UpdateVenueRequest) (*models.Venue, error) {
+	venue, err := s.venueRepo.GetVenueByID(ctx, id)
+	if err != nil {
+		return nil, ErrVenueNotFound
+	}
+	before := *venue
+
+	if req.Name != nil {
+		venue.Name = *req.Name
+	}
+	if req.Address != nil {
+		venue.Address = *req.Address
+	}
+	if req.Latitude != nil {
+		venue.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		venue.Longitude = *req.Longitude
+	}
+	if req.Timezone != nil {
+		venue.Timezone = *req.Timezone
+	}
+	if req.Phone != nil {
+		venue.Phone = *req.Phone
+	}
+	if req.Email != nil {
+		venue.Email = *req.Email
+	}
+	if req.Status != nil {
+		venue.Status = *req.Status
+	}
+	if req.OpenTime != nil {
+		venue.OpenTime = *req.OpenTime
+	}
+	if req.CloseTime != nil {
+		venue.CloseTime = *req.CloseTime
+	}
+	if err := validateVenue(venue); err != nil {
+		return nil, err
+	}
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "venue.update", "venue", venue.ID, before, venue)
+	}
+	if err := s.venueRepo.UpdateVenue(ctx, venue, audit); err != nil {
+		return nil, fmt.Errorf("failed to update venue: %v", err)
+	}
+	return venue, nil
+}
+
+// SetUserRole promotes or demotes a user. Staff must be assigned to a venue and can only
+// manage that venue; customers and admins are not tied to one.
+func (s *venueService) SetUserRole(ctx context.Context, actorID uint, userID uint, req *models.SetUserRoleRequest) (*models.UserResponse, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	before := *user
+
+	var venueID *uint
+	if req.Role == "staff" {
+		if req.VenueID == nil {
+			return nil, errors.New("venue_id is required for staff")
+		}
+		if _, err := s.venueRepo.GetVenueByID(ctx, *req.VenueID); err != nil {
+			return nil, ErrVenueNotFound
+		}
+		venueID = req.VenueID
+	}
+
+	user.Role = req.Role
+	user.VenueID = venueID
+
+	audit := func() (*models.AuditLog, error) {
+		return newAuditLog(actorID, "user.role", "user", userID, before, user)
+	}
+	if err := s.userRepo.UpdateUserRole(ctx, userID, req.Role, venueID, audit); err != nil {
+		return nil, fmt.Errorf("failed to update role: %v", err)
+	}
+	return &models.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Role:      user.Role,
+		VenueID:   user.VenueID,
+		CreatedAt: user.CreatedAt,
+	}, nil
+}
+
+func validateVenue(venue *models.Venue) error {
+	if _, err := time.LoadLocation(venue.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q", venue.Timezone)
+	}
+	if _, _, err := utils.ParseTimeSlot(venue.OpenTime + "-" + venue.CloseTime); err != nil {
+		return fmt.Errorf("invalid opening hours: %v", err)
+	}
+	return nil
+}
+
+// staffVenue returns the venue an account is limited to: nil for admins, the assigned
+// venue for staff.
+func staffVenue(ctx context.Context, userRepo repositories.UserRepository, actorID uint) (*uint, error) {
+	actor, err := userRepo.GetUserByID(ctx, actorID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if actor.Role == "admin" {
+		return nil, nil
+	}
+	if actor.VenueID == nil {
+		return nil, errors.New("staff account is not assigned to a venue")
+	}
+	return actor.VenueID, nil
+}
+
+// checkVenue reports ErrOtherVenue when a court of venueID is outside the actor's scope.
+func checkVenue(scope *uint, venueID *uint) error {
+	if scope == nil {
+		return nil
+	}
+	if venueID == nil || *venueID != *scope {
+		return ErrOtherVenue
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"backend/internal/models"
+	"backend/internal/repositories"
+	"backend/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrWaitlistEntryNotFound is returned when the entry does not exist or belongs to another user.
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	// ErrSlotAvailable is returned when joining the waitlist for a slot that can be booked now.
+	ErrSlotAvailable = errors.New("selected timeslot is available, book it directly")
+	// ErrAlreadyWaiting is returned when the user already waits for an overlapping slot.
+	ErrAlreadyWaiting = errors.New("already on the waitlist for this timeslot")
+	// ErrOfferNotOpen is returned when claiming an entry without an open offer.
+	ErrOfferNotOpen = errors.New("no open offer for this waitlist entry")
+)
+
+// WaitlistService queues users for booked slots. When a slot frees up (a cancellation or a
+// lapsed hold) the first user waiting for it is offered the slot: it is held for them as
+// a pending reservation for the claim window. A claimed offer is paid like any booking.
+type WaitlistService interface {
+	JoinWaitlist(ctx context.Context, userID uint, req *models.JoinWaitlistRequest) (*models.WaitlistEntryResponse, error)
+	GetUserEntries(ctx context.Context, userID uint) ([]models.WaitlistEntryResponse, error)
+	LeaveWaitlist(ctx context.Context, userID uint, id uint) error
+	ClaimOffer(ctx context.Context, userID uint, id uint) (*models.ReservationResponse, error)
+	OfferFreedSlot(ctx context.Context, courtID uint, date time.Time)
+	ProcessWaitlist(ctx context.Context) (int, error)
+}
+
+type waitlistService struct {
+	waitlistRepo    repositories.WaitlistRepository
+	reservationRepo repositories.ReservationRepository
+	courtRepo       repositories.CourtRepository
+	closureRepo     repositories.ClosureRepository
+	pricingService  PricingService
+	noShowPolicy    NoShowPolicy
+	claimWindow     time.Duration
+	holdTTL         time.Duration
+}
+
+func NewWaitlistService(
+	waitlistRepo repositories.WaitlistRepository,
+	reservationRepo repositories.ReservationRepository,
+	courtRepo repositories.CourtRepository,
+	closureRepo repositories.ClosureRepository,
+	pricingService PricingService,
+	noShowPolicy NoShowPolicy,
+	claimWindow time.Duration,
+	holdTTL time.Duration,
+) WaitlistService {
+	return &waitlistService{
+		waitlistRepo:    waitlistRepo,
+		reservationRepo: reservationRepo,
+		courtRepo:       courtRepo,
+		closureRepo:     closureRepo,
+		pricingService:  pricingService,
+		noShowPolicy:    noShowPolicy,
+		claimWindow:     claimWindow,
+		holdTTL:         holdTTL,
+	}
+}
+
+func toWaitlistEntryResponse(entry *models.WaitlistEntry, position int) models.WaitlistEntryResponse {
+	return models.WaitlistEntryResponse{
+		ID:              entry.ID,
+		CourtID:         entry.CourtID,
+		CourtName:       entry.Court.Name,
+		ReservationDate: entry.ReservationDate.Format("2006-01-02"),
+		TimeSlot:        entry.TimeSlot,
+		Status:          entry.Status,
+		Position:        position,
+		ReservationID:   entry.ReservationID,
+		OfferExpiresAt:  entry.OfferExpiresAt,
+		CreatedAt:       entry.CreatedAt,
+	}
+}
+
+// JoinWaitlist queues the user for a slot that is currently taken. The slot must be
+// bookable apart from the existing reservation.
+func (s *waitlistService) JoinWaitlist(ctx context.Context, userID uint, req *models.JoinWaitlistRequest) (*models.WaitlistEntryResponse, error) {
+	if err := s.noShowPolicy.Check(ctx, s.reservationRepo, userID); err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return nil, errors.New("invalid date format. Use YYYY-MM-DD")
+	}
+
+	start, end, err := resolveTimeSlot(&req.CreateReservationRequest)
+	if err != nil {
+		return nil, err
+	}
+	timeSlot := utils.FormatTimeSlot(start, end)
+
+	court, err := s.courtRepo.GetCourtByID(ctx, req.CourtID)
+	if err != nil {
+		return nil, ErrCourtNotFound
+	}
+	startAt := slotTime(date, start, courtLocation(court))
+	if !startAt.After(time.Now()) {
+		return nil, errors.New("timeslot has already started")
+	}
+	schedule, err := loadDaySchedule(ctx, s.courtRepo, s.closureRepo, court, date)
+	if err != nil {
+		return nil, err
+	}
+	if err := schedule.Validate(start, end); err != nil {
+		return nil, err
+	}
+
+	isBooked, err := s.reservationRepo.CheckExistingReservation(ctx, date, timeSlot, req.CourtID)
+	if err != nil {
+		return nil, errors.New("failed to check availability")
+	}
+	if !isBooked {
+		return nil, ErrSlotAvailable
+	}
+
+	queue, err := s.waitlistRepo.GetActiveEntriesOn(ctx, req.CourtID, date)
+	if err != nil {
+		return nil, errors.New("failed to check waitlist")
+	}
+	for _, queued := range queue {
+		if queued.UserID == userID && entriesOverlap(&queued, start, end) {
+			return nil, ErrAlreadyWaiting
+		}
+	}
+
+	entry := &models.WaitlistEntry{
+		UserID:          userID,
+		CourtID:         req.CourtID,
+		ReservationDate: date,
+		TimeSlot:        timeSlot,
+		StartAt:         startAt,
+		Status:          "waiting",
+	}
+	if err := s.waitlistRepo.CreateEntry(ctx, entry); err != nil {
+		return nil, errors.New("failed to join waitlist")
+	}
+	entry.Court = *court
+
+	response := toWaitlistEntryResponse(entry, queuePosition(queue, entry))
+	return &response, nil
+}
+
+// entriesOverlap reports whether an entry's time slot intersects [start, end).
+func entriesOverlap(entry *models.WaitlistEntry, start, end int) bool {
+	entryStart, entryEnd, err := utils.ParseTimeSlot(entry.TimeSlot)
+	if err != nil {
+		return false
+	}
+	return utils.RangesOverlap(start, end, entryStart, entryEnd)
+}
+
+// queuePosition is 1 plus the number of entries waiting ahead of entry for an
+// overlapping slot.
+func queuePosition(queue []models.WaitlistEntry, entry *models.WaitlistEntry) int {
+	start, end, err := utils.ParseTimeSlot(entry.TimeSlot)
+	if err != nil {
+		return 0
+	}
+
+	position := 1
+	for _, queued := range queue {
+		if queued.ID == entry.ID {
+			break
+		}
+		if queued.Status == "waiting" && entriesOverlap(&queued, start, end) {
+			position++
+		}
+	}
+	return position
+}
+
+func (s *waitlistService) GetUserEntries(ctx context.Context, userID uint) ([]models.WaitlistEntryResponse, error) {
+	entries, err := s.waitlistRepo.GetUserEntries(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get waitlist entries")
+	}
+
+	responses := make([]models.WaitlistEntryResponse, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		position := 0
+		if entry.Status == "waiting" {
+			queue, err := s.waitlistRepo.GetActiveEntriesOn(ctx, entry.CourtID, entry.ReservationDate)
+			if err != nil {
+				return nil, errors.New("failed to get waitlist entries")
+			}
+			position = queuePosition(queue, entry)
+		}
+		responses = append(responses, toWaitlistEntryResponse(entry, position))
+	}
+	return responses, nil
+}
+
+func (s *waitlistService) loadEntry(ctx context.Context, userID uint, id uint) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetEntryByID(ctx, id)
+	if err != nil || entry.UserID != userID {
+		return nil, ErrWaitlistEntryNotFound
+	}
+	return entry, nil
+}
+
+// LeaveWaitlist removes the user's entry. Leaving with an open offer releases the held
+// slot, which is then offered to the next user in line.
+func (s *waitlistService) LeaveWaitlist(ctx context.Context, userID uint, id uint) error {
+	entry, err := s.loadEntry(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if entry.Status != "waiting" && entry.Status != "offered" {
+		return fmt.Errorf("waitlist entry is already %s", entry.Status)
+	}
+
+	if err := s.waitlistRepo.CancelEntry(ctx, entry); err != nil {
+		if errors.Is(err, repositories.ErrWaitlistEntryChanged) {
+			return errors.New("waitlist entry changed, please try again")
+		}
+		return errors.New("failed to leave waitlist")
+	}
+
+	if entry.Status == "offered" {
+		s.OfferFreedSlot(ctx, entry.CourtID, entry.ReservationDate)
+	}
+	return nil
+}
+
+// ClaimOffer accepts an open offer. The held reservation then stays reserved for the
+// regular payment window and is paid through POST /payments.
+func (s *waitlistService) ClaimOffer(ctx context.Context, userID uint, id uint) (*models.ReservationResponse, error) {
+	entry, err := s.loadEntry(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if entry.Status != "offered" || entry.OfferExpiresAt == nil || !entry.OfferExpiresAt.After(now) {
+		return nil, ErrOfferNotOpen
+	}
+
+	err = s.waitlistRepo.ClaimOffer(ctx, entry, now, now.Add(s.holdTTL))
+	if errors.Is(err, repositories.ErrWaitlistEntryChanged) {
+		return nil, ErrOfferNotOpen
+	}
+	if err != nil {
+		return nil, errors.New("failed to claim offer")
+	}
+
+	reservation, err := s.reservationRepo.GetReservationByID(ctx, *entry.ReservationID)
+	if err != nil {
+		return nil, errors.New("failed to fetch reservation")
+	}
+
+	response := toReservationResponse(reservation)
+	return &response, nil
+}
+
+// OfferFreedSlot offers a court's freed time on date to the users waiting for it. Failures
+// are only logged; the waitlist job retries on its next run.
+func (s *waitlistService) OfferFreedSlot(ctx context.Context, courtID uint, date time.Time) {
+	queue, err := s.waitlistRepo.GetActiveEntriesOn(ctx, courtID, date)
+	if err != nil {
+		fmt.Printf("ERROR loading waitlist for court %d: %v\n", courtID, err)
+		return
+	}
+
+	now := time.Now()
+	var waiting []models.WaitlistEntry
+	for _, entry := range queue {
+		if entry.Status == "waiting" && entry.StartAt.After(now) {
+			waiting = append(waiting, entry)
+		}
+	}
+	s.offerEntries(ctx, waiting)
+}
+
+// ProcessWaitlist expires entries whose slot has started and offers nobody claimed in
+// time, then offers every slot that has freed up since. It returns the number of offers made.
+func (s *waitlistService) ProcessWaitlist(ctx context.Context) (int, error) {
+	now := time.Now()
+	if _, err := s.waitlistRepo.ExpireEntries(ctx, now); err != nil {
+		return 0, fmt.Errorf("failed to expire waitlist entries: %v", err)
+	}
+
+	waiting, err := s.waitlistRepo.GetWaitingEntries(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load waitlist: %v", err)
+	}
+	return s.offerEntries(ctx, waiting), nil
+}
+
+// offerEntries offers each waiting entry its slot if the slot can be booked now. Entries
+// are given in queue order, so the first user waiting for a slot gets it.
+func (s *waitlistService) offerEntries(ctx context.Context, entries []models.WaitlistEntry) int {
+	offered := 0
+	for i := range entries {
+		entry := &entries[i]
+		ok, err := s.offer(ctx, entry)
+		switch {
+		case err == nil:
+			if ok {
+				offered++
+			}
+		case errors.Is(err, ErrSlotTaken), errors.Is(err, repositories.ErrWaitlistEntryChanged):
+			// Booked in the meantime, or the entry was handled elsewhere
+		default:
+			fmt.Printf("ERROR offering waitlist entry %d: %v\n", entry.ID, err)
+		}
+	}
+	return offered
+}
+
+// offer holds the entry's slot for its user if the slot can be booked now. It reports
+// false without an error when the slot is still taken or closed.
+func (s *waitlistService) offer(ctx context.Context, entry *models.WaitlistEntry) (bool, error) {
+	reservation, err := quoteReservation(ctx, s.courtRepo, s.closureRepo, s.reservationRepo, s.pricingService, &models.CreateReservationRequest{
+		CourtID:  entry.CourtID,
+		Date:     entry.ReservationDate.Format("2006-01-02"),
+		TimeSlot: entry.TimeSlot,
+	})
+	if err != nil {
+		return false, nil
+	}
+
+	offerExpiresAt := time.Now().Add(s.claimWindow)
+	reservation.UserID = entry.UserID
+	reservation.Status = "pending"
+	reservation.HoldExpiresAt = &offerExpiresAt
+
+	if err := s.waitlistRepo.OfferEntry(ctx, entry, reservation, offerExpiresAt); err != nil {
+		return false, err
+	}
+
+	fmt.Printf("📣 Offered court %d on %s %s to user %d (waitlist entry %d) until %s\n",
+		entry.CourtID, entry.ReservationDate.Format("2006-01-02"), entry.TimeSlot,
+		entry.UserID, entry.ID, offerExpiresAt.Format(time.RFC3339))
+	return true, nil
+}
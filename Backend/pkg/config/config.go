@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +16,48 @@ type Config struct {
 	MidtransClientKey string
 	MidtransEnv       string
 	Port              string
+
+	// MidtransTimeout bounds each HTTP request to Midtrans
+	MidtransTimeout time.Duration
+	// MidtransMaxRetries is how many times failed idempotent Midtrans calls are retried
+	MidtransMaxRetries int
+
+	// PaymentGateway selects the payment provider: "midtrans" or "fake" (offline development, sandbox only)
+	PaymentGateway string
+	// FakeGatewayOutcome is the notification the fake gateway sends after a charge: settlement, expire, deny or none
+	FakeGatewayOutcome string
+	// FakeGatewayDelay is how long the fake gateway waits before sending it
+	FakeGatewayDelay time.Duration
+
+	// MidtransTestNotifications accepts Midtrans dashboard test notifications (sandbox only)
+	MidtransTestNotifications bool
+
+	// ReservationHoldTTL is how long an unpaid reservation blocks its slot
+	ReservationHoldTTL time.Duration
+	// HoldSweepInterval is how often lapsed holds are released
+	HoldSweepInterval time.Duration
+	// WaitlistClaimWindow is how long a freed slot is held for the waitlisted user it is offered to
+	WaitlistClaimWindow time.Duration
+	// SplitPaymentWindow is the default time players get to pay their share of a split reservation
+	SplitPaymentWindow time.Duration
+
+	// CheckInSecret signs the check-in codes customers show at the front desk
+	CheckInSecret string
+	// CheckInOpensBefore is how long before the start of a booking check-in opens
+	CheckInOpensBefore time.Duration
+	// AttendanceSweepInterval is how often finished bookings are marked completed or no-show
+	AttendanceSweepInterval time.Duration
+	// NoShowLimit is how many no-shows within NoShowWindow block new bookings (0 disables)
+	NoShowLimit int
+	// NoShowWindow is how far back no-shows are counted
+	NoShowWindow time.Duration
+
+	// PaymentReconcileInterval is how often pending payments are re-checked against Midtrans
+	PaymentReconcileInterval time.Duration
+	// PaymentStaleAfter is how old a pending payment must be before it is re-checked
+	PaymentStaleAfter time.Duration
+	// RefundRetryInterval is how often refunds the gateway rejected are sent again
+	RefundRetryInterval time.Duration
 }
 
 func Load() *Config {
@@ -30,9 +74,46 @@ func Load() *Config {
 		MidtransClientKey: getEnv("MIDTRANS_CLIENT_KEY", ""),
 		MidtransEnv:       getEnv("MIDTRANS_ENV", "sandbox"),
 		Port:              getEnv("PORT", "8080"),
+
+		MidtransTimeout:    getEnvDuration("MIDTRANS_TIMEOUT", 15*time.Second),
+		MidtransMaxRetries: getEnvInt("MIDTRANS_MAX_RETRIES", 2),
+
+		PaymentGateway:     getEnv("PAYMENT_GATEWAY", "midtrans"),
+		FakeGatewayOutcome: getEnv("FAKE_GATEWAY_OUTCOME", "settlement"),
+		FakeGatewayDelay:   getEnvDuration("FAKE_GATEWAY_DELAY", 5*time.Second),
+
+		MidtransTestNotifications: getEnv("MIDTRANS_TEST_NOTIFICATIONS", "false") == "true",
+
+		ReservationHoldTTL: getEnvDuration("RESERVATION_HOLD_TTL", 15*time.Minute),
+		HoldSweepInterval:  getEnvDuration("HOLD_SWEEP_INTERVAL", time.Minute),
+
+		WaitlistClaimWindow: getEnvDuration("WAITLIST_CLAIM_WINDOW", 30*time.Minute),
+		SplitPaymentWindow:  getEnvDuration("SPLIT_PAYMENT_WINDOW", 24*time.Hour),
+
+		CheckInSecret:           getEnv("CHECKIN_SECRET", getEnv("JWT_SECRET", "fallback-secret-key-change-in-production")),
+		CheckInOpensBefore:      getEnvDuration("CHECKIN_OPENS_BEFORE", 30*time.Minute),
+		AttendanceSweepInterval: getEnvDuration("ATTENDANCE_SWEEP_INTERVAL", 5*time.Minute),
+		NoShowLimit:             getEnvInt("NO_SHOW_LIMIT", 3),
+		NoShowWindow:            getEnvDuration("NO_SHOW_WINDOW", 90*24*time.Hour),
+
+		PaymentReconcileInterval: getEnvDuration("PAYMENT_RECONCILE_INTERVAL", 5*time.Minute),
+		PaymentStaleAfter:        getEnvDuration("PAYMENT_STALE_AFTER", 10*time.Minute),
+		RefundRetryInterval:      getEnvDuration("REFUND_RETRY_INTERVAL", 5*time.Minute),
 	}
 }
 
+// TestNotificationsEnabled reports whether Midtrans test notifications should be acknowledged.
+// They are never accepted outside the sandbox environment.
+func (c *Config) TestNotificationsEnabled() bool {
+	return c.MidtransEnv == "sandbox" && c.MidtransTestNotifications
+}
+
+// FakeGatewayAllowed reports whether the fake payment gateway may be used. It settles any
+// order on request, so it is refused outside the sandbox environment.
+func (c *Config) FakeGatewayAllowed() bool {
+	return c.MidtransEnv == "sandbox"
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -40,3 +121,29 @@ func getEnv(key, defaultValue string) string {
 	}
 	return value
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Warning: invalid %s %q, using %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 0 {
+		log.Printf("Warning: invalid %s %q, using %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return number
+}
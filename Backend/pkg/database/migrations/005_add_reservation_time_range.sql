@@ -0,0 +1,17 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS start_at TIMESTAMPTZ,  -- Awal booking (tanggal + jam mulai)
+    ADD COLUMN IF NOT EXISTS end_at TIMESTAMPTZ;    -- Akhir booking
+
+-- Backfill existing rows from reservation_date + time_slot (HH:MM-HH:MM)
+UPDATE reservations
+SET start_at = reservation_date + split_part(time_slot, '-', 1)::time,
+    end_at   = reservation_date + split_part(time_slot, '-', 2)::time
+WHERE start_at IS NULL;
+
+-- No two active reservations may overlap on the same court
+ALTER TABLE reservations
+    ADD CONSTRAINT reservations_no_overlap
+    EXCLUDE USING gist (court_id WITH =, tstzrange(start_at, end_at) WITH &&)
+    WHERE (status IN ('pending', 'confirmed'));
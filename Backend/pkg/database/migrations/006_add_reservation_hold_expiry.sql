@@ -0,0 +1,11 @@
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS hold_expires_at TIMESTAMPTZ; -- Batas waktu pembayaran untuk reservasi pending
+
+CREATE INDEX IF NOT EXISTS idx_reservations_hold_expires_at ON reservations (hold_expires_at);
+
+-- Reservasi pending lama belum punya batas waktu; beri hold default (15 menit) sejak dibuat
+UPDATE reservations
+SET hold_expires_at = created_at + INTERVAL '15 minutes'
+WHERE status = 'pending' AND hold_expires_at IS NULL;
+
+-- status: pending, confirmed, cancelled, expired
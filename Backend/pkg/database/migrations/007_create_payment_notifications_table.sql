@@ -0,0 +1,13 @@
+CREATE TABLE payment_notifications (
+    id SERIAL PRIMARY KEY,
+    order_id VARCHAR(100),              -- Order ID dari payload (bisa kosong)
+    transaction_status VARCHAR(30),
+    raw_body TEXT,                      -- Body mentah dari Midtrans
+    headers TEXT,                       -- Header request (JSON)
+    signature_valid BOOLEAN,            -- NULL jika belum diverifikasi
+    outcome VARCHAR(20),                -- processed, test, rejected, failed
+    error TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_notifications_order_id ON payment_notifications (order_id);
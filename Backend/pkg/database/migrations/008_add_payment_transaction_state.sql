@@ -0,0 +1,7 @@
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS transaction_id VARCHAR(100),    -- transaction_id dari Midtrans
+    ADD COLUMN IF NOT EXISTS transaction_status VARCHAR(30); -- transaction_status terakhir yang diproses
+
+CREATE INDEX IF NOT EXISTS idx_payments_transaction_id ON payments (transaction_id);
+
+-- status: pending, paid, expired, failed, refunded, partially_refunded
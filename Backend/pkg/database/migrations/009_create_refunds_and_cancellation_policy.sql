@@ -0,0 +1,18 @@
+ALTER TABLE courts
+    ADD COLUMN IF NOT EXISTS full_refund_hours INT DEFAULT 24,     -- Refund penuh jika batal >= 24 jam sebelumnya
+    ADD COLUMN IF NOT EXISTS partial_refund_hours INT DEFAULT 2,   -- Refund sebagian jika batal >= 2 jam sebelumnya
+    ADD COLUMN IF NOT EXISTS partial_refund_percent INT DEFAULT 50;
+
+CREATE TABLE refunds (
+    id SERIAL PRIMARY KEY,
+    payment_id INT REFERENCES payments(id) ON DELETE CASCADE,
+    reservation_id INT REFERENCES reservations(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    reason VARCHAR(255),
+    status VARCHAR(20) DEFAULT 'pending', -- pending, succeeded, failed
+    refund_key VARCHAR(100) UNIQUE,       -- refund_key yang dikirim ke Midtrans
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refunds_payment_id ON refunds (payment_id);
@@ -0,0 +1,3 @@
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS snap_token VARCHAR(100),  -- Token Snap untuk dipakai ulang
+    ADD COLUMN IF NOT EXISTS redirect_url TEXT;
@@ -0,0 +1,29 @@
+CREATE TABLE payment_channels (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,       -- bca_va, gopay, qris, ...
+    name VARCHAR(100) NOT NULL,
+    payment_method VARCHAR(50) NOT NULL,    -- bank_transfer, gopay, shopeepay, qris, credit_card
+    bank VARCHAR(20) DEFAULT '',            -- Bank VA untuk bank_transfer
+    fee_flat DECIMAL(10,2) DEFAULT 0,       -- Biaya tetap per transaksi
+    fee_percent DECIMAL(5,2) DEFAULT 0,     -- Biaya persentase dari total
+    enabled BOOLEAN DEFAULT TRUE,           -- Channel nonaktif tidak ditampilkan
+    sort_order INT DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO payment_channels (code, name, payment_method, bank, fee_flat, fee_percent, sort_order) VALUES
+    ('bca_va', 'BCA Virtual Account', 'bank_transfer', 'bca', 4000, 0, 10),
+    ('bni_va', 'BNI Virtual Account', 'bank_transfer', 'bni', 4000, 0, 20),
+    ('bri_va', 'BRI Virtual Account', 'bank_transfer', 'bri', 4000, 0, 30),
+    ('mandiri_va', 'Mandiri Bill Payment', 'bank_transfer', 'mandiri', 4000, 0, 40),
+    ('permata_va', 'Permata Virtual Account', 'bank_transfer', 'permata', 4000, 0, 50),
+    ('gopay', 'GoPay', 'gopay', '', 0, 2, 60),
+    ('shopeepay', 'ShopeePay', 'shopeepay', '', 0, 2, 70),
+    ('qris', 'QRIS', 'qris', '', 0, 0.7, 80),
+    ('credit_card', 'Credit / Debit Card', 'credit_card', '', 2000, 2.9, 90)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS fee DECIMAL(10,2) DEFAULT 0,  -- Biaya channel yang ditambahkan ke total
+    ADD COLUMN IF NOT EXISTS biller_code VARCHAR(20);      -- Kode biller Mandiri Bill Payment
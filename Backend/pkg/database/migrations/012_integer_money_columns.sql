@@ -0,0 +1,10 @@
+-- Semua nominal uang disimpan sebagai rupiah bulat (BIGINT), bukan DECIMAL
+ALTER TABLE courts ALTER COLUMN price_per_hour TYPE BIGINT USING ROUND(price_per_hour)::BIGINT;
+ALTER TABLE reservations ALTER COLUMN total_amount TYPE BIGINT USING ROUND(total_amount)::BIGINT;
+ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT USING ROUND(amount)::BIGINT;
+ALTER TABLE payments ALTER COLUMN fee TYPE BIGINT USING ROUND(fee)::BIGINT;
+ALTER TABLE refunds ALTER COLUMN amount TYPE BIGINT USING ROUND(amount)::BIGINT;
+ALTER TABLE payment_channels ALTER COLUMN fee_flat TYPE BIGINT USING ROUND(fee_flat)::BIGINT;
+
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) DEFAULT 'IDR'; -- Midtrans hanya mendukung IDR
@@ -0,0 +1,40 @@
+CREATE TABLE pricing_rules (
+    id SERIAL PRIMARY KEY,
+    court_id INT REFERENCES courts(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    day_type VARCHAR(10) DEFAULT '',    -- '', weekday, weekend, holiday
+    start_time VARCHAR(5) DEFAULT '',   -- HH:MM, kosong = sepanjang hari
+    end_time VARCHAR(5) DEFAULT '',     -- HH:MM, 24:00 = akhir hari
+    start_date DATE,                    -- Override untuk rentang tanggal tertentu
+    end_date DATE,
+    price_per_hour BIGINT NOT NULL,
+    priority INT DEFAULT 0,             -- Rule dengan prioritas tertinggi yang dipakai
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pricing_rules_court_id ON pricing_rules (court_id);
+
+CREATE TABLE holidays (
+    id SERIAL PRIMARY KEY,
+    date DATE UNIQUE NOT NULL,
+    name VARCHAR(100)
+);
+
+-- Rincian harga per segmen waktu, disimpan saat reservasi dibuat
+CREATE TABLE reservation_price_lines (
+    id SERIAL PRIMARY KEY,
+    reservation_id INT REFERENCES reservations(id) ON DELETE CASCADE,
+    time_slot VARCHAR(20) NOT NULL,
+    price_per_hour BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    pricing_rule_id INT REFERENCES pricing_rules(id) ON DELETE SET NULL,
+    rule_name VARCHAR(100)
+);
+
+CREATE INDEX idx_reservation_price_lines_reservation_id ON reservation_price_lines (reservation_id);
+
+-- Contoh: jam sibuk malam hari dan tarif akhir pekan
+-- INSERT INTO pricing_rules (court_id, name, start_time, end_time, price_per_hour, priority)
+--     VALUES (1, 'Peak hours', '17:00', '22:00', 80000, 10);
+-- INSERT INTO pricing_rules (court_id, name, day_type, price_per_hour, priority)
+--     VALUES (1, 'Weekend', 'weekend', 70000, 5);
@@ -0,0 +1,30 @@
+ALTER TABLE courts
+    ADD COLUMN IF NOT EXISTS slot_minutes INT DEFAULT 60;  -- Panjang slot: 30, 60 atau 90 menit
+
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS duration_minutes INT;
+
+UPDATE reservations SET duration_minutes = duration_hours * 60 WHERE duration_minutes IS NULL;
+
+-- Jam operasional mingguan; court tanpa data memakai 07:00-21:00
+CREATE TABLE court_operating_hours (
+    id SERIAL PRIMARY KEY,
+    court_id INT REFERENCES courts(id) ON DELETE CASCADE,
+    weekday INT NOT NULL,             -- 0 = Minggu ... 6 = Sabtu
+    open_time VARCHAR(5) NOT NULL,    -- HH:MM
+    close_time VARCHAR(5) NOT NULL,   -- HH:MM, 24:00 = tengah malam
+    closed BOOLEAN DEFAULT FALSE,
+    UNIQUE (court_id, weekday)
+);
+
+-- Jam khusus untuk tanggal tertentu, menggantikan jadwal mingguan
+CREATE TABLE court_special_hours (
+    id SERIAL PRIMARY KEY,
+    court_id INT REFERENCES courts(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    open_time VARCHAR(5),
+    close_time VARCHAR(5),
+    closed BOOLEAN DEFAULT FALSE,
+    note VARCHAR(255),
+    UNIQUE (court_id, date)
+);
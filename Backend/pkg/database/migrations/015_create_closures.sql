@@ -0,0 +1,18 @@
+-- Penutupan lapangan (maintenance) atau seluruh venue (libur nasional)
+CREATE TABLE closures (
+    id SERIAL PRIMARY KEY,
+    court_id INT REFERENCES courts(id) ON DELETE CASCADE,  -- NULL = semua lapangan
+    start_at TIMESTAMPTZ NOT NULL,
+    end_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_closures_court_id ON closures (court_id);
+CREATE INDEX idx_closures_start_at ON closures (start_at);
+
+-- Reservasi yang terkena penutupan ditandai untuk reschedule atau refund
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS closure_id INT REFERENCES closures(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_reservations_closure_id ON reservations (closure_id);
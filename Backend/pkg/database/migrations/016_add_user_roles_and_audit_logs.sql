@@ -0,0 +1,22 @@
+-- Role pengguna: customer, staff, admin
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'customer';
+
+-- Admin pertama dipromosikan manual, contoh:
+-- UPDATE users SET role = 'admin' WHERE email = 'admin@example.com';
+
+-- Jejak perubahan lewat API admin
+CREATE TABLE audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id INT NOT NULL REFERENCES users(id),
+    action VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id INT,
+    before TEXT,  -- snapshot JSON sebelum perubahan
+    after TEXT,   -- snapshot JSON sesudah perubahan
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_entity ON audit_logs (entity_type, entity_id);
+CREATE INDEX idx_audit_logs_created_at ON audit_logs (created_at);
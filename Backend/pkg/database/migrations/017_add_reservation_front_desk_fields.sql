@@ -0,0 +1,10 @@
+-- Booking dari front desk: tamu walk-in tanpa akun dan staf yang membuat booking
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS guest_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS guest_phone VARCHAR(30),
+    ADD COLUMN IF NOT EXISTS created_by_id INT REFERENCES users(id);
+
+-- Pencarian reservasi lintas user di console admin
+CREATE INDEX idx_reservations_reservation_date ON reservations (reservation_date);
+
+-- Pembayaran tunai dicatat dengan payment_method = 'cash' dan order id CASH-<reservasi>-<timestamp>
@@ -0,0 +1,9 @@
+-- Detail lapangan untuk halaman court
+ALTER TABLE courts
+    ADD COLUMN IF NOT EXISTS description TEXT,
+    ADD COLUMN IF NOT EXISTS environment VARCHAR(20) DEFAULT 'indoor',  -- indoor, outdoor
+    ADD COLUMN IF NOT EXISTS floor_type VARCHAR(50),                    -- synthetic, wood, vinyl, cement
+    ADD COLUMN IF NOT EXISTS lighting VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS has_ac BOOLEAN DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS capacity INT DEFAULT 4,
+    ADD COLUMN IF NOT EXISTS photos TEXT;                               -- JSON array URL foto
@@ -0,0 +1,34 @@
+-- Venue (gedung/hall) yang memiliki lapangan
+CREATE TABLE venues (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    address VARCHAR(255) NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    timezone VARCHAR(50) NOT NULL DEFAULT 'Asia/Jakarta',
+    phone VARCHAR(30),
+    email VARCHAR(100),
+    status VARCHAR(20) DEFAULT 'active',  -- active, inactive
+    open_time VARCHAR(5) NOT NULL DEFAULT '07:00',   -- jam buka default lapangan
+    close_time VARCHAR(5) NOT NULL DEFAULT '21:00',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE courts
+    ADD COLUMN IF NOT EXISTS venue_id INT REFERENCES venues(id);
+CREATE INDEX idx_courts_venue_id ON courts (venue_id);
+
+-- Staf hanya mengelola venue tempatnya bekerja
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS venue_id INT REFERENCES venues(id);
+CREATE INDEX idx_users_venue_id ON users (venue_id);
+
+-- Penutupan untuk seluruh lapangan di satu venue
+ALTER TABLE closures
+    ADD COLUMN IF NOT EXISTS venue_id INT REFERENCES venues(id) ON DELETE CASCADE;
+CREATE INDEX idx_closures_venue_id ON closures (venue_id);
+
+-- Lapangan lama dipindahkan ke venue pertama (lihat ensureDefaultVenue)
+INSERT INTO venues (name, address)
+SELECT 'Main Hall', '-' WHERE NOT EXISTS (SELECT 1 FROM venues);
+UPDATE courts SET venue_id = (SELECT MIN(id) FROM venues) WHERE venue_id IS NULL;
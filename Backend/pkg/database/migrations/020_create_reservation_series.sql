@@ -0,0 +1,25 @@
+-- Booking berulang (mingguan / dua mingguan), misalnya klub yang main tiap Selasa
+CREATE TABLE reservation_series (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    court_ids TEXT NOT NULL,              -- JSON array, misalnya [1,2]
+    frequency VARCHAR(10) NOT NULL,       -- weekly, biweekly
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,               -- tanggal occurrence terakhir
+    start_time VARCHAR(5) NOT NULL,
+    end_time VARCHAR(5) NOT NULL,
+    total_amount BIGINT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending', -- pending, confirmed, cancelled
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_reservation_series_user_id ON reservation_series (user_id);
+
+-- Setiap occurrence adalah reservasi biasa yang menunjuk ke series-nya
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS series_id INT REFERENCES reservation_series(id);
+CREATE INDEX idx_reservations_series_id ON reservations (series_id);
+
+-- Satu pembayaran untuk seluruh series, dicatat pada occurrence pertama
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS series_id INT REFERENCES reservation_series(id);
+CREATE INDEX idx_payments_series_id ON payments (series_id);
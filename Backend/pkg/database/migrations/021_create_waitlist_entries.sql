@@ -0,0 +1,16 @@
+-- Antrian untuk slot yang sudah penuh
+CREATE TABLE waitlist_entries (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    court_id INT NOT NULL REFERENCES courts(id),
+    reservation_date DATE NOT NULL,
+    time_slot VARCHAR(20) NOT NULL,
+    start_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) DEFAULT 'waiting',  -- waiting, offered, claimed, expired, cancelled
+    reservation_id INT REFERENCES reservations(id),  -- reservasi yang ditahan selama penawaran
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX idx_waitlist_entries_user_id ON waitlist_entries (user_id);
+CREATE INDEX idx_waitlist_entries_status ON waitlist_entries (status);
+CREATE INDEX idx_waitlist_entries_court_date ON waitlist_entries (court_id, reservation_date);
@@ -0,0 +1,12 @@
+-- Batas waktu reschedule oleh customer (jam sebelum mulai)
+ALTER TABLE courts
+    ADD COLUMN IF NOT EXISTS reschedule_cutoff_hours INT DEFAULT 12;
+
+-- Kekurangan bayar setelah reservasi dipindah ke slot yang lebih mahal
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS amount_due BIGINT DEFAULT 0;
+
+-- Tujuan pembayaran: booking, top_up (selisih harga setelah reschedule)
+-- Order id top-up: TOPUP-<reservasi>-<timestamp>
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS purpose VARCHAR(20) DEFAULT 'booking';
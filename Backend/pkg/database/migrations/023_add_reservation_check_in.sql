@@ -0,0 +1,14 @@
+-- Check-in di front desk: status checked_in, lalu completed atau no_show setelah slot selesai
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS checked_in_by_id INT REFERENCES users(id);
+
+-- Booking yang sudah check-in tetap memblokir lapangan sampai selesai
+ALTER TABLE reservations DROP CONSTRAINT IF EXISTS reservations_no_overlap;
+ALTER TABLE reservations
+    ADD CONSTRAINT reservations_no_overlap
+    EXCLUDE USING gist (court_id WITH =, tstzrange(start_at, end_at) WITH &&)
+    WHERE (status IN ('pending', 'confirmed', 'checked_in'));
+
+-- Hitung no-show per user
+CREATE INDEX idx_reservations_user_status ON reservations (user_id, status);
@@ -0,0 +1,20 @@
+-- Split payment: biaya lapangan dibagi rata antar pemain, organiser menutup sisanya setelah deadline
+ALTER TABLE reservations
+    ADD COLUMN IF NOT EXISTS split_deadline TIMESTAMPTZ;
+
+CREATE TABLE reservation_participants (
+    id SERIAL PRIMARY KEY,
+    reservation_id INT NOT NULL REFERENCES reservations(id),
+    user_id INT NOT NULL REFERENCES users(id),
+    share_amount BIGINT NOT NULL,
+    status VARCHAR(20) DEFAULT 'pending',  -- pending, paid, covered
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX idx_participants_reservation_user ON reservation_participants (reservation_id, user_id);
+CREATE INDEX idx_reservation_participants_user_id ON reservation_participants (user_id);
+
+-- Pembayaran bagian satu pemain (purpose share)
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS participant_id INT REFERENCES reservation_participants(id);
+CREATE INDEX idx_payments_participant_id ON payments (participant_id);
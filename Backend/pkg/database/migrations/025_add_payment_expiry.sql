@@ -0,0 +1,7 @@
+-- Waktu kedaluwarsa order Midtrans, mengikuti hold reservasi (NULL = default gateway)
+ALTER TABLE payments
+    ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
+
+-- Hanya satu pembayaran pending per reservasi dan purpose, agar request bersamaan tidak membuat dua order
+CREATE UNIQUE INDEX idx_payments_one_pending_per_reservation ON payments (reservation_id, purpose)
+    WHERE status = 'pending' AND participant_id IS NULL AND series_id IS NULL;
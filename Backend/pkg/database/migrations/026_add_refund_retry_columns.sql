@@ -0,0 +1,4 @@
+-- Refund yang ditolak gateway tetap pending dan dicoba ulang oleh job retrier
+ALTER TABLE refunds
+    ADD COLUMN IF NOT EXISTS attempts INT DEFAULT 0,     -- Berapa kali refund sudah dikirim ke Midtrans
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;     -- Waktu percobaan terakhir
@@ -0,0 +1,3 @@
+-- Hanya satu pembayaran pending per series, agar request bersamaan tidak membuat dua order
+CREATE UNIQUE INDEX idx_payments_one_pending_per_series ON payments (series_id)
+    WHERE status = 'pending' AND series_id IS NOT NULL;
@@ -3,11 +3,14 @@ package database
 import (
 	"backend/internal/models"
 	"backend/pkg/config"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -27,7 +30,7 @@ func ConnectDB(cfg *config.Config) *gorm.DB {
 	fmt.Println("✅ Connected to database successfully")
 
 	// Auto migrate tables
-	err = autoMigrate(db)
+	err = autoMigrate(db, cfg.ReservationHoldTTL)
 	if err != nil {
 		log.Fatal("Failed to auto migrate:", err)
 	}
@@ -35,18 +38,236 @@ func ConnectDB(cfg *config.Config) *gorm.DB {
 	return db
 }
 
-func autoMigrate(db *gorm.DB) error {
+func autoMigrate(db *gorm.DB, holdTTL time.Duration) error {
+	// Convert legacy DECIMAL money columns before GORM compares column types
+	if err := ensureIntegerMoneyColumns(db); err != nil {
+		return err
+	}
+
 	// Auto migrate all tables
 	err := db.AutoMigrate(
 		&models.User{},
+		&models.Venue{},
 		&models.Court{},
 		&models.Reservation{},
+		&models.ReservationSeries{},
 		&models.Payment{},
+		&models.PaymentNotification{},
+		&models.Refund{},
+		&models.PaymentChannel{},
+		&models.PricingRule{},
+		&models.Holiday{},
+		&models.ReservationPriceLine{},
+		&models.CourtOperatingHours{},
+		&models.CourtSpecialHours{},
+		&models.Closure{},
+		&models.AuditLog{},
+		&models.WaitlistEntry{},
+		&models.ReservationParticipant{},
 	)
 	if err != nil {
 		return err
 	}
 
+	// Pending reservations made before holds existed would block their court forever
+	result := db.Exec(`UPDATE reservations SET hold_expires_at = created_at + ? * INTERVAL '1 second'
+		WHERE status = 'pending' AND hold_expires_at IS NULL`, holdTTL.Seconds())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		fmt.Printf("⏰ Gave %d pending reservation(s) without a hold a %s hold\n", result.RowsAffected, holdTTL)
+	}
+
+	if err := ensureReservationOverlapConstraint(db); err != nil {
+		return err
+	}
+
+	if err := ensureSinglePendingPayment(db); err != nil {
+		return err
+	}
+
+	// Reservations made before minute durations existed
+	err = db.Exec(`UPDATE reservations SET duration_minutes = duration_hours * 60
+		WHERE duration_minutes IS NULL OR duration_minutes = 0`).Error
+	if err != nil {
+		return err
+	}
+
+	if err := ensureDefaultVenue(db); err != nil {
+		return err
+	}
+
+	if err := seedPaymentChannels(db); err != nil {
+		return err
+	}
+
 	fmt.Println("✅ Database tables migrated successfully")
 	return nil
 }
+
+// ensureDefaultVenue moves courts created before venues existed into the first venue,
+// creating one if there is none yet.
+func ensureDefaultVenue(db *gorm.DB) error {
+	var orphans int64
+	if err := db.Model(&models.Court{}).Where("venue_id IS NULL").Count(&orphans).Error; err != nil {
+		return err
+	}
+	if orphans == 0 {
+		return nil
+	}
+
+	var venue models.Venue
+	err := db.Order("id ASC").First(&venue).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		venue = models.Venue{
+			Name:      "Main Hall",
+			Address:   "-",
+			Timezone:  "Asia/Jakarta",
+			Status:    "active",
+			OpenTime:  "07:00",
+			CloseTime: "21:00",
+		}
+		err = db.Create(&venue).Error
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("🏟️ Assigning %d court(s) without a venue to %s\n", orphans, venue.Name)
+	return db.Model(&models.Court{}).Where("venue_id IS NULL").Update("venue_id", venue.ID).Error
+}
+
+// ensureReservationOverlapConstraint lets Postgres reject overlapping active
+// reservations on the same court, even under concurrent inserts.
+func ensureReservationOverlapConstraint(db *gorm.DB) error {
+	statements := []string{
+		`CREATE EXTENSION IF NOT EXISTS btree_gist`,
+		`UPDATE reservations
+		SET start_at = reservation_date + split_part(time_slot, '-', 1)::time,
+			end_at = reservation_date + split_part(time_slot, '-', 2)::time
+		WHERE start_at IS NULL`,
+		`DO $$
+		BEGIN
+			-- Older schemas did not count checked-in bookings as active
+			IF EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'reservations_no_overlap'
+				AND pg_get_constraintdef(oid) NOT LIKE '%checked_in%') THEN
+				ALTER TABLE reservations DROP CONSTRAINT reservations_no_overlap;
+			END IF;
+			IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'reservations_no_overlap') THEN
+				ALTER TABLE reservations
+					ADD CONSTRAINT reservations_no_overlap
+					EXCLUDE USING gist (court_id WITH =, tstzrange(start_at, end_at) WITH &&)
+					WHERE (status IN ('pending', 'confirmed', 'checked_in'));
+			END IF;
+		END $$`,
+	}
+
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingPaymentIndexes allow one pending payment per reservation and purpose, and one per
+// series, so concurrent requests cannot open two Midtrans orders for the same booking.
+// Shares are keyed by participant and not covered.
+var pendingPaymentIndexes = []struct {
+	name    string
+	columns string
+	where   string
+}{
+	{"idx_payments_one_pending_per_reservation", "reservation_id, purpose", "status = 'pending' AND participant_id IS NULL AND series_id IS NULL"},
+	{"idx_payments_one_pending_per_series", "series_id", "status = 'pending' AND series_id IS NOT NULL"},
+}
+
+// ensureSinglePendingPayment creates the pendingPaymentIndexes. Duplicates left by older
+// versions are not expired here, since either order may still be paid; an index is created
+// on a later start once they have settled or lapsed.
+func ensureSinglePendingPayment(db *gorm.DB) error {
+	for _, index := range pendingPaymentIndexes {
+		var duplicates int64
+		err := db.Raw(fmt.Sprintf(`SELECT COUNT(*) FROM (
+				SELECT 1 FROM payments WHERE %s
+				GROUP BY %s HAVING COUNT(*) > 1
+			) AS duplicated`, index.where, index.columns)).Scan(&duplicates).Error
+		if err != nil {
+			return err
+		}
+		if duplicates > 0 {
+			fmt.Printf("⚠️ %d booking(s) have several pending payments, skipping index %s\n", duplicates, index.name)
+			continue
+		}
+
+		err = db.Exec(fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS %s ON payments (%s) WHERE %s`,
+			index.name, index.columns, index.where)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moneyColumns are the amounts stored as whole rupiah (money.Amount).
+var moneyColumns = map[string][]string{
+	"courts":           {"price_per_hour"},
+	"reservations":     {"total_amount"},
+	"payments":         {"amount", "fee"},
+	"refunds":          {"amount"},
+	"payment_channels": {"fee_flat"},
+}
+
+// ensureIntegerMoneyColumns converts DECIMAL money columns from older schemas to BIGINT,
+// rounding to whole rupiah.
+func ensureIntegerMoneyColumns(db *gorm.DB) error {
+	for table, columns := range moneyColumns {
+		for _, column := range columns {
+			var dataType string
+			err := db.Raw(`SELECT data_type FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?`, table, column).
+				Scan(&dataType).Error
+			if err != nil {
+				return err
+			}
+			if dataType != "numeric" {
+				continue
+			}
+
+			statement := fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN %s TYPE BIGINT USING ROUND(%s)::BIGINT`, table, column, column)
+			if err := db.Exec(statement).Error; err != nil {
+				return err
+			}
+			fmt.Printf("✅ Converted %s.%s to integer rupiah\n", table, column)
+		}
+	}
+	return nil
+}
+
+// defaultPaymentChannels is the initial payment method catalogue. Fees and enable
+// flags are edited in the payment_channels table afterwards; seeding never overwrites them.
+var defaultPaymentChannels = []models.PaymentChannel{
+	{Code: "bca_va", Name: "BCA Virtual Account", PaymentMethod: "bank_transfer", Bank: "bca", FeeFlat: 4000, SortOrder: 10},
+	{Code: "bni_va", Name: "BNI Virtual Account", PaymentMethod: "bank_transfer", Bank: "bni", FeeFlat: 4000, SortOrder: 20},
+	{Code: "bri_va", Name: "BRI Virtual Account", PaymentMethod: "bank_transfer", Bank: "bri", FeeFlat: 4000, SortOrder: 30},
+	{Code: "mandiri_va", Name: "Mandiri Bill Payment", PaymentMethod: "bank_transfer", Bank: "mandiri", FeeFlat: 4000, SortOrder: 40},
+	{Code: "permata_va", Name: "Permata Virtual Account", PaymentMethod: "bank_transfer", Bank: "permata", FeeFlat: 4000, SortOrder: 50},
+	{Code: "gopay", Name: "GoPay", PaymentMethod: "gopay", FeePercent: 2, SortOrder: 60},
+	{Code: "shopeepay", Name: "ShopeePay", PaymentMethod: "shopeepay", FeePercent: 2, SortOrder: 70},
+	{Code: "qris", Name: "QRIS", PaymentMethod: "qris", FeePercent: 0.7, SortOrder: 80},
+	{Code: "credit_card", Name: "Credit / Debit Card", PaymentMethod: "credit_card", FeeFlat: 2000, FeePercent: 2.9, SortOrder: 90},
+}
+
+func seedPaymentChannels(db *gorm.DB) error {
+	channels := make([]models.PaymentChannel, len(defaultPaymentChannels))
+	for i, channel := range defaultPaymentChannels {
+		channel.Enabled = true
+		channels[i] = channel
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(&channels).Error
+}
@@ -2,14 +2,26 @@ package midtrans
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
 )
 
+// ErrTransactionNotFound is returned when Midtrans has no transaction for the order ID.
+var ErrTransactionNotFound = errors.New("transaction not found at Midtrans")
+
 type Client interface {
-	CreateTransaction(request *ChargeRequest) (*ChargeResponse, error)
+	CreateSnapTransaction(ctx context.Context, request *SnapRequest) (*SnapResponse, error)
+	CreateTransaction(ctx context.Context, request *ChargeRequest) (*ChargeResponse, error)
+	GetTransactionStatus(ctx context.Context, orderID string) (*TransactionStatusResponse, error)
+	RefundTransaction(ctx context.Context, orderID string, request *RefundRequest) (*RefundResponse, error)
+	ExpireTransaction(ctx context.Context, orderID string) (*TransactionStatusResponse, error)
 	HandleNotification(payload map[string]interface{}) (*Notification, error)
 }
 
@@ -21,7 +33,7 @@ type client struct {
 func NewClient(config *Config) Client {
 	return &client{
 		config:     config,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: config.Timeout},
 	}
 }
 
@@ -32,35 +44,48 @@ func (c *client) getBaseURL() string {
 	return "https://api.sandbox.midtrans.com/v2"
 }
 
-func (c *client) CreateTransaction(request *ChargeRequest) (*ChargeResponse, error) {
-	url := c.getBaseURL() + "/charge"
+func (c *client) getSnapURL() string {
+	if c.config.IsProduction() {
+		return "https://app.midtrans.com/snap/v1/transactions"
+	}
+	return "https://app.sandbox.midtrans.com/snap/v1/transactions"
+}
 
-	jsonData, err := json.Marshal(request)
+// CreateSnapTransaction creates a Snap token and redirect URL for the popup checkout.
+// It is not retried: Snap rejects a second transaction with the same order ID.
+func (c *client) CreateSnapTransaction(ctx context.Context, request *SnapRequest) (*SnapResponse, error) {
+	status, body, err := c.send(ctx, http.MethodPost, c.getSnapURL(), request, "", false)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %v", err)
+		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	var snapResponse SnapResponse
+	err = json.Unmarshal(body, &snapResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
 	}
 
-	req.SetBasicAuth(c.config.ServerKey, "")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %v", err)
+	if status != http.StatusCreated || snapResponse.Token == "" {
+		if len(snapResponse.ErrorMessages) > 0 {
+			return nil, fmt.Errorf("midtrans API error: %s", strings.Join(snapResponse.ErrorMessages, "; "))
+		}
+		return nil, fmt.Errorf("midtrans API error: %s", string(body))
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	return &snapResponse, nil
+}
+
+// CreateTransaction charges through the Core API. The order ID doubles as the idempotency key,
+// so a retried charge returns the original transaction instead of a duplicate.
+func (c *client) CreateTransaction(ctx context.Context, request *ChargeRequest) (*ChargeResponse, error) {
+	url := c.getBaseURL() + "/charge"
+
+	status, body, err := c.send(ctx, http.MethodPost, url, request, request.TransactionDetails.OrderID, true)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
+	if status != 200 {
 		return nil, fmt.Errorf("midtrans API error: %s", string(body))
 	}
 
@@ -70,9 +95,99 @@ func (c *client) CreateTransaction(request *ChargeRequest) (*ChargeResponse, err
 		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
 	}
 
+	// Core API reports failures with HTTP 200 and an error status_code in the body
+	if chargeResponse.StatusCode != "200" && chargeResponse.StatusCode != "201" {
+		return nil, fmt.Errorf("midtrans API error: %s %s", chargeResponse.StatusCode, chargeResponse.StatusMessage)
+	}
+
 	return &chargeResponse, nil
 }
 
+func (c *client) GetTransactionStatus(ctx context.Context, orderID string) (*TransactionStatusResponse, error) {
+	url := c.getBaseURL() + "/" + neturl.PathEscape(orderID) + "/status"
+
+	status, body, err := c.send(ctx, http.MethodGet, url, nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("midtrans API error: %s", string(body))
+	}
+
+	var statusResponse TransactionStatusResponse
+	err = json.Unmarshal(body, &statusResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	// Midtrans reports unknown orders with HTTP 200 and a 404 status_code in the body
+	if statusResponse.StatusCode == "404" {
+		return nil, ErrTransactionNotFound
+	}
+
+	return &statusResponse, nil
+}
+
+// RefundTransaction refunds a settled transaction. The refund key makes retries safe.
+func (c *client) RefundTransaction(ctx context.Context, orderID string, request *RefundRequest) (*RefundResponse, error) {
+	url := c.getBaseURL() + "/" + neturl.PathEscape(orderID) + "/refund"
+
+	status, body, err := c.send(ctx, http.MethodPost, url, request, request.RefundKey, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("midtrans API error: %s", string(body))
+	}
+
+	var refundResponse RefundResponse
+	err = json.Unmarshal(body, &refundResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if refundResponse.StatusCode == "404" {
+		return nil, ErrTransactionNotFound
+	}
+	if refundResponse.StatusCode != "200" {
+		return nil, fmt.Errorf("midtrans API error: %s %s", refundResponse.StatusCode, refundResponse.StatusMessage)
+	}
+
+	return &refundResponse, nil
+}
+
+// ExpireTransaction expires a transaction that is still pending, so it can no longer be
+// paid. Midtrans refuses to expire one that has already settled.
+func (c *client) ExpireTransaction(ctx context.Context, orderID string) (*TransactionStatusResponse, error) {
+	url := c.getBaseURL() + "/" + neturl.PathEscape(orderID) + "/expire"
+
+	status, body, err := c.send(ctx, http.MethodPost, url, nil, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != 200 {
+		return nil, fmt.Errorf("midtrans API error: %s", string(body))
+	}
+
+	var expireResponse TransactionStatusResponse
+	err = json.Unmarshal(body, &expireResponse)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	if expireResponse.StatusCode == "404" {
+		return nil, ErrTransactionNotFound
+	}
+	if expireResponse.TransactionStatus != "expire" {
+		return nil, fmt.Errorf("midtrans API error: %s %s", expireResponse.StatusCode, expireResponse.StatusMessage)
+	}
+
+	return &expireResponse, nil
+}
+
 func (c *client) HandleNotification(payload map[string]interface{}) (*Notification, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -87,3 +202,81 @@ func (c *client) HandleNotification(payload map[string]interface{}) (*Notificati
 
 	return &notification, nil
 }
+
+// send executes an authenticated request and returns the HTTP status and body.
+// When retry is set, network errors and 429/5xx responses are retried with exponential backoff.
+func (c *client) send(ctx context.Context, method, url string, payload interface{}, idempotencyKey string, retry bool) (int, []byte, error) {
+	var jsonData []byte
+	if payload != nil {
+		var err error
+		jsonData, err = json.Marshal(payload)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+	}
+
+	attempts := 1
+	if retry {
+		attempts += c.config.MaxRetries
+	}
+	backoff := c.config.RetryBackoff
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return 0, nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		status, body, err := c.do(ctx, method, url, jsonData, idempotencyKey)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if status == http.StatusTooManyRequests || status >= 500 {
+			lastErr = fmt.Errorf("midtrans API error: HTTP %d: %s", status, string(body))
+			continue
+		}
+		return status, body, nil
+	}
+
+	return 0, nil, lastErr
+}
+
+func (c *client) do(ctx context.Context, method, url string, jsonData []byte, idempotencyKey string) (int, []byte, error) {
+	var reqBody io.Reader
+	if jsonData != nil {
+		reqBody = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	req.SetBasicAuth(c.config.ServerKey, "")
+	req.Header.Set("Accept", "application/json")
+	if jsonData != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to execute request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	return resp.StatusCode, body, nil
+}
@@ -1,30 +1,49 @@
 package midtrans
 
 import (
-	"os"
+	"errors"
+	"time"
 )
 
 type Config struct {
 	ServerKey string
 	ClientKey string
 	Env       string
+
+	// Timeout bounds a single HTTP request to Midtrans
+	Timeout time.Duration
+	// MaxRetries is how many times a failed idempotent request is retried
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry; it doubles on each attempt
+	RetryBackoff time.Duration
 }
 
-func NewConfig() *Config {
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-	clientKey := os.Getenv("MIDTRANS_CLIENT_KEY")
-	env := os.Getenv("MIDTRANS_ENV")
+func NewConfig(serverKey, clientKey, env string) *Config {
 	if env == "" {
 		env = "sandbox"
 	}
 
 	return &Config{
-		ServerKey: serverKey,
-		ClientKey: clientKey,
-		Env:       env,
+		ServerKey:    serverKey,
+		ClientKey:    clientKey,
+		Env:          env,
+		Timeout:      15 * time.Second,
+		MaxRetries:   2,
+		RetryBackoff: 500 * time.Millisecond,
 	}
 }
 
 func (c *Config) IsProduction() bool {
 	return c.Env == "production"
 }
+
+// Validate checks that the config can be used to talk to Midtrans.
+func (c *Config) Validate() error {
+	if c.ServerKey == "" {
+		return errors.New("MIDTRANS_SERVER_KEY is not set")
+	}
+	if c.Env != "sandbox" && c.Env != "production" {
+		return errors.New("MIDTRANS_ENV must be sandbox or production")
+	}
+	return nil
+}
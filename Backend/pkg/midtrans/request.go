@@ -1,5 +1,7 @@
 package midtrans
 
+import "backend/pkg/money"
+
 type ChargeRequest struct {
 	PaymentType        string             `json:"payment_type"`
 	TransactionDetails TransactionDetails `json:"transaction_details"`
@@ -7,11 +9,13 @@ type ChargeRequest struct {
 	ItemDetails        []ItemDetail       `json:"item_details,omitempty"`
 	BankTransfer       *BankTransfer      `json:"bank_transfer,omitempty"`
 	EWallet            *EWallet           `json:"ewallet,omitempty"`
+	EChannel           *EChannel          `json:"echannel,omitempty"`
+	CustomExpiry       *CustomExpiry      `json:"custom_expiry,omitempty"`
 }
 
 type TransactionDetails struct {
-	OrderID  string `json:"order_id"`
-	GrossAmt int64  `json:"gross_amount"`
+	OrderID  string       `json:"order_id"`
+	GrossAmt money.Amount `json:"gross_amount"`
 }
 
 type CustomerDetails struct {
@@ -22,16 +26,53 @@ type CustomerDetails struct {
 }
 
 type ItemDetail struct {
-	ID    string `json:"id"`
-	Price int64  `json:"price"`
-	Qty   int    `json:"quantity"`
-	Name  string `json:"name"`
+	ID    string       `json:"id"`
+	Price money.Amount `json:"price"`
+	Qty   int          `json:"quantity"`
+	Name  string       `json:"name"`
 }
 
 type BankTransfer struct {
 	Bank string `json:"bank,omitempty"` // bca, bni, bri, etc.
 }
 
+// EChannel is Mandiri Bill Payment; the customer pays with a biller code and bill key.
+type EChannel struct {
+	BillInfo1 string `json:"bill_info1"`
+	BillInfo2 string `json:"bill_info2"`
+}
+
+// CustomExpiry overrides how long a Core API order stays payable, counted from the
+// transaction time when OrderTime is empty.
+type CustomExpiry struct {
+	OrderTime      string `json:"order_time,omitempty"`
+	ExpiryDuration int    `json:"expiry_duration"`
+	Unit           string `json:"unit"` // second, minute, hour or day
+}
+
+// SnapExpiry overrides how long a Snap order stays payable, counted from the time the
+// token is created when StartTime is empty.
+type SnapExpiry struct {
+	StartTime string `json:"start_time,omitempty"`
+	Unit      string `json:"unit"` // minute, hour or day
+	Duration  int    `json:"duration"`
+}
+
 type EWallet struct {
 	Channel string `json:"channel,omitempty"` // gopay, shopeepay, etc.
 }
+
+// SnapRequest creates a Snap transaction; EnabledPayments limits the methods shown in the popup.
+type SnapRequest struct {
+	TransactionDetails TransactionDetails `json:"transaction_details"`
+	CustomerDetails    *CustomerDetails   `json:"customer_details,omitempty"`
+	ItemDetails        []ItemDetail       `json:"item_details,omitempty"`
+	EnabledPayments    []string           `json:"enabled_payments,omitempty"`
+	Expiry             *SnapExpiry        `json:"expiry,omitempty"`
+}
+
+type RefundRequest struct {
+	RefundKey string       `json:"refund_key"`
+	Amount    money.Amount `json:"amount"`
+	Reason    string       `json:"reason,omitempty"`
+}
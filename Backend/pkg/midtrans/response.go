@@ -1,11 +1,32 @@
 package midtrans
 
 type ChargeResponse struct {
-	Token       string     `json:"token"`
-	RedirectURL string     `json:"redirect_url"`
-	OrderID     string     `json:"order_id"`
-	StatusCode  string     `json:"status_code"`
-	VaNumbers   []VaNumber `json:"va_numbers"`
+	Token             string     `json:"token"`
+	RedirectURL       string     `json:"redirect_url"`
+	OrderID           string     `json:"order_id"`
+	TransactionID     string     `json:"transaction_id"`
+	TransactionStatus string     `json:"transaction_status"`
+	StatusCode        string     `json:"status_code"`
+	StatusMessage     string     `json:"status_message"`
+	VaNumbers         []VaNumber `json:"va_numbers"`
+	PermataVaNumber   string     `json:"permata_va_number"`
+	BillKey           string     `json:"bill_key"`
+	BillerCode        string     `json:"biller_code"`
+}
+
+type SnapResponse struct {
+	Token         string   `json:"token"`
+	RedirectURL   string   `json:"redirect_url"`
+	ErrorMessages []string `json:"error_messages"`
+}
+
+type RefundResponse struct {
+	StatusCode    string `json:"status_code"`
+	StatusMessage string `json:"status_message"`
+	TransactionID string `json:"transaction_id"`
+	OrderID       string `json:"order_id"`
+	RefundKey     string `json:"refund_key"`
+	RefundAmount  string `json:"refund_amount"`
 }
 
 type Notification struct {
@@ -21,6 +42,7 @@ type Notification struct {
 	FraudStatus       string `json:"fraud_status"`
 	Currency          string `json:"currency"`
 	PaymentType       string `json:"payment_type"`
+	SettlementTime    string `json:"settlement_time"`
 }
 
 type TransactionStatusResponse struct {
@@ -30,6 +52,10 @@ type TransactionStatusResponse struct {
 	GrossAmount       string `json:"gross_amount"`
 	PaymentType       string `json:"payment_type"`
 	TransactionTime   string `json:"transaction_time"`
+	StatusCode        string `json:"status_code"`
+	StatusMessage     string `json:"status_message"`
+	FraudStatus       string `json:"fraud_status"`
+	SettlementTime    string `json:"settlement_time"`
 }
 
 type VaNumber struct {
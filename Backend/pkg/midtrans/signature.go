@@ -0,0 +1,23 @@
+package midtrans
+
+import (
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+)
+
+// Signature computes the Midtrans notification signature:
+// SHA512(order_id + status_code + gross_amount + server_key), hex encoded.
+func Signature(orderID, statusCode, grossAmount, serverKey string) string {
+	sum := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifySignature reports whether the notification's signature_key was produced with serverKey.
+func (n *Notification) VerifySignature(serverKey string) bool {
+	if n.SignatureKey == "" || serverKey == "" {
+		return false
+	}
+	expected := Signature(n.OrderID, n.StatusCode, n.GrossAmount, serverKey)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(n.SignatureKey)) == 1
+}
@@ -0,0 +1,41 @@
+package midtrans
+
+import "testing"
+
+func TestVerifySignature(t *testing.T) {
+	const serverKey = "SB-Mid-server-test"
+	signed := func() Notification {
+		return Notification{
+			OrderID:      "ORDER-42-1700000000",
+			StatusCode:   "200",
+			GrossAmount:  "150000.00",
+			SignatureKey: Signature("ORDER-42-1700000000", "200", "150000.00", serverKey),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tamper    func(n *Notification)
+		serverKey string
+		want      bool
+	}{
+		{name: "valid", tamper: func(n *Notification) {}, serverKey: serverKey, want: true},
+		{name: "tampered signature", tamper: func(n *Notification) { n.SignatureKey = "0" + n.SignatureKey[1:] }, serverKey: serverKey},
+		{name: "tampered gross amount", tamper: func(n *Notification) { n.GrossAmount = "1000.00" }, serverKey: serverKey},
+		{name: "tampered order id", tamper: func(n *Notification) { n.OrderID = "ORDER-43-1700000000" }, serverKey: serverKey},
+		{name: "tampered status code", tamper: func(n *Notification) { n.StatusCode = "201" }, serverKey: serverKey},
+		{name: "missing signature", tamper: func(n *Notification) { n.SignatureKey = "" }, serverKey: serverKey},
+		{name: "other server key", tamper: func(n *Notification) {}, serverKey: "SB-Mid-server-other"},
+		{name: "no server key", tamper: func(n *Notification) {}, serverKey: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notif := signed()
+			tt.tamper(&notif)
+			if got := notif.VerifySignature(tt.serverKey); got != tt.want {
+				t.Errorf("VerifySignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
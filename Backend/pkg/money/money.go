@@ -0,0 +1,54 @@
+package money
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Currency is the only currency the app charges in. Rupiah has no minor unit in
+// practice, so amounts are stored and sent to Midtrans as whole rupiah.
+const Currency = "IDR"
+
+// Amount is a sum of money in whole rupiah. It is stored as BIGINT and serialized as a JSON number.
+type Amount int64
+
+// Mul returns the amount multiplied by a whole quantity (hours, items, ...).
+func (a Amount) Mul(quantity int) Amount {
+	return a * Amount(quantity)
+}
+
+// Percent returns percent of the amount, rounded down to whole rupiah.
+func (a Amount) Percent(percent int) Amount {
+	return a * Amount(percent) / 100
+}
+
+// PercentRounded returns a fractional percentage (e.g. a 2.9% fee) of the amount,
+// rounded half up to whole rupiah.
+func (a Amount) PercentRounded(percent float64) Amount {
+	basisPoints := int64(percent*100 + 0.5)
+	return Amount((int64(a)*basisPoints + 5000) / 10000)
+}
+
+// GrossAmount formats the amount the way Midtrans reports gross_amount, e.g. "150000.00".
+func (a Amount) GrossAmount() string {
+	return fmt.Sprintf("%d.00", int64(a))
+}
+
+func (a Amount) String() string {
+	return fmt.Sprintf("%s %d", Currency, int64(a))
+}
+
+// ParseGrossAmount parses a Midtrans gross_amount ("150000.00" or "150000") exactly,
+// without going through floating point. Non-zero fractions are rejected.
+func ParseGrossAmount(value string) (Amount, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if strings.Trim(fraction, "0") != "" {
+		return 0, fmt.Errorf("gross amount %q is not a whole rupiah amount", value)
+	}
+	amount, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gross amount %q", value)
+	}
+	return Amount(amount), nil
+}
@@ -0,0 +1,95 @@
+package money
+
+import "testing"
+
+func TestGrossAmount(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{0, "0.00"},
+		{1, "1.00"},
+		{150000, "150000.00"},
+		{9999999999, "9999999999.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.GrossAmount(); got != tt.want {
+			t.Errorf("Amount(%d).GrossAmount() = %q, want %q", int64(tt.amount), got, tt.want)
+		}
+	}
+}
+
+func TestParseGrossAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Amount
+		wantErr bool
+	}{
+		{value: "150000.00", want: 150000},
+		{value: "150000", want: 150000},
+		{value: "150000.0", want: 150000},
+		{value: " 150000.00 ", want: 150000},
+		{value: "0.00", want: 0},
+		{value: "9999999999.00", want: 9999999999},
+		{value: "150000.50", wantErr: true},
+		{value: "150000.01", wantErr: true},
+		{value: "1.5e5", wantErr: true},
+		{value: "150,000.00", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseGrossAmount(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseGrossAmount(%q) = %d, want an error", tt.value, int64(got))
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseGrossAmount(%q) returned error %v", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseGrossAmount(%q) = %d, want %d", tt.value, int64(got), int64(tt.want))
+		}
+	}
+}
+
+func TestGrossAmountRoundTrip(t *testing.T) {
+	for _, amount := range []Amount{0, 1, 999, 4000, 150000, 152900, 1234567, 9999999999} {
+		got, err := ParseGrossAmount(amount.GrossAmount())
+		if err != nil {
+			t.Errorf("round trip of %d failed: %v", int64(amount), err)
+			continue
+		}
+		if got != amount {
+			t.Errorf("round trip of %d returned %d", int64(amount), int64(got))
+		}
+	}
+}
+
+func TestPercentRounded(t *testing.T) {
+	tests := []struct {
+		amount  Amount
+		percent float64
+		want    Amount
+	}{
+		{100000, 2.9, 2900},
+		{150000, 0.7, 1050},   // 0.7*100 is not exact in floating point
+		{12345, 2.9, 358},     // 358.005 rounds down
+		{333, 1.5, 5},         // 4.995 rounds up
+		{20, 2.5, 1},          // exactly half rounds up
+		{19, 2.5, 0},          // 0.475 rounds down
+		{150000, 0, 0},        // flat-fee channels
+		{150000, 100, 150000}, // whole amount
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.PercentRounded(tt.percent); got != tt.want {
+			t.Errorf("Amount(%d).PercentRounded(%v) = %d, want %d", int64(tt.amount), tt.percent, int64(got), int64(tt.want))
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const MinutesPerDay = 24 * 60
+
+// ParseClock parses "HH:MM" into minutes since midnight. "24:00" is accepted as end of day.
+func ParseClock(value string) (int, error) {
+	if len(value) != 5 || value[2] != ':' {
+		return 0, errors.New("invalid time format. Use HH:MM")
+	}
+	hour, err := strconv.Atoi(value[:2])
+	if err != nil || value[0] < '0' || value[0] > '9' {
+		return 0, errors.New("invalid time format. Use HH:MM")
+	}
+	minute, err := strconv.Atoi(value[3:])
+	if err != nil || value[3] < '0' || value[3] > '9' {
+		return 0, errors.New("invalid time format. Use HH:MM")
+	}
+	if hour > 24 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, errors.New("invalid time format. Use HH:MM")
+	}
+	return hour*60 + minute, nil
+}
+
+// FormatClock formats minutes since midnight as "HH:MM".
+func FormatClock(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// ParseTimeSlot parses "HH:MM-HH:MM" into start and end minutes since midnight.
+func ParseTimeSlot(slot string) (int, int, error) {
+	parts := strings.Split(slot, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("invalid timeslot format. Use HH:MM-HH:MM")
+	}
+
+	start, err := ParseClock(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, errors.New("invalid timeslot format. Use HH:MM-HH:MM")
+	}
+	end, err := ParseClock(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, errors.New("invalid timeslot format. Use HH:MM-HH:MM")
+	}
+	if end <= start {
+		return 0, 0, errors.New("timeslot end must be after start")
+	}
+
+	return start, end, nil
+}
+
+// FormatTimeSlot formats start and end minutes as "HH:MM-HH:MM".
+func FormatTimeSlot(start, end int) string {
+	return FormatClock(start) + "-" + FormatClock(end)
+}
+
+// RangesOverlap reports whether the half-open ranges [aStart, aEnd) and [bStart, bEnd) intersect.
+func RangesOverlap(aStart, aEnd, bStart, bEnd int) bool {
+	return aStart < bEnd && bStart < aEnd
+}